@@ -10,8 +10,11 @@ import (
 	"time"
 
 	"finamhackbackend/internal/config"
+	"finamhackbackend/internal/editorial"
+	"finamhackbackend/internal/egress"
 	"finamhackbackend/internal/llm"
 	"finamhackbackend/internal/radar"
+	"finamhackbackend/internal/store"
 	transporthttp "finamhackbackend/internal/transport/http"
 )
 
@@ -21,39 +24,131 @@ func main() {
 		log.Fatalf("load config: %v", err)
 	}
 
-	staticSource, err := radar.NewStaticFileSource("sample", cfg.StaticDataPath)
-	if err != nil {
-		log.Fatalf("init static source: %v", err)
+	timestamps := radar.TimestampOptions{Strict: cfg.StrictTimestamps, DefaultZone: cfg.TimestampZone}
+	var baseSource radar.Source
+	var replay *radar.ReplayController
+	if cfg.ReplayDataPath != "" {
+		items, first, last, err := radar.LoadReplayItems(cfg.ReplayDataPath, timestamps)
+		if err != nil {
+			log.Fatalf("init replay: %v", err)
+		}
+		start := first
+		if !cfg.ReplayStart.IsZero() {
+			start = cfg.ReplayStart
+		}
+		replay = radar.NewReplayController(start, last, cfg.ReplaySpeed, nil)
+		replaySource, err := radar.NewReplaySource("replay", items, replay)
+		if err != nil {
+			log.Fatalf("init replay source: %v", err)
+		}
+		baseSource = replaySource
+		log.Printf("Replay mode: %d items from %s at %.0fx, paused until started", len(items), start.Format(time.RFC3339), cfg.ReplaySpeed)
+	} else {
+		staticSource, err := radar.NewStaticFileSource("sample", cfg.StaticDataPath)
+		if err != nil {
+			log.Fatalf("init static source: %v", err)
+		}
+		staticSource.Timestamps = timestamps
+		baseSource = staticSource
 	}
 
 	ingestSource := radar.NewIngestSource("ingest")
 
-	sources, err := radar.NewSourceRegistry(staticSource, ingestSource)
+	sources, err := radar.NewSourceRegistry(baseSource, ingestSource)
 	if err != nil {
 		log.Fatalf("init source registry: %v", err)
 	}
 
+	// one transport for all outbound calls so proxy/CA settings apply everywhere;
+	// HTTP-backed sources should take their client from egress.NewClient too
+	transport, err := egress.NewTransport(egress.Options{
+		HTTPProxy:          cfg.HTTPProxy,
+		HTTPSProxy:         cfg.HTTPSProxy,
+		CABundle:           cfg.CABundle,
+		InsecureSkipVerify: cfg.TLSInsecure,
+	})
+	if err != nil {
+		log.Fatalf("init egress transport: %v", err)
+	}
+	if cfg.TLSInsecure {
+		log.Printf("WARNING: TLS certificate verification is disabled for outbound requests")
+	}
+	llmHTTP := llm.WithHTTPClient(egress.NewClient(transport, 30*time.Second))
+
+	state, err := store.Open(cfg.StatePath)
+	if err != nil {
+		log.Fatalf("open state store: %v", err)
+	}
+
+	// shared by the serving and shadow LLM clusterers: quality is tracked per model;
+	// nil when RADAR_LLM_MAX_ITEMS <= 0, so requests stay uncapped
+	batchTuner := radar.NewBatchTuner(cfg.LLMMinItems, cfg.LLMMaxItems, cfg.LLMForgetRate, state)
+
 	clusterer := radar.DefaultClusterer()
 	if cfg.VibeRouterAPIKey != "" {
-		llmClient := llm.NewClient(cfg.VibeRouterAPIKey)
+		llmClient := llm.NewClient(cfg.VibeRouterAPIKey, llmHTTP)
 		clusterer = &radar.LLMClusterer{
 			Client:      llmClient,
 			Model:       cfg.VibeRouterModel,
 			Temperature: cfg.LLMTemperature,
+			TopP:        cfg.LLMTopP,
 			MaxTokens:   cfg.LLMMaxTokens,
 			MaxItems:    cfg.LLMMaxItems,
 			Fallback:    radar.NewHeuristicClusterer(6*time.Hour, 0.45),
 			CacheTTL:    2 * time.Minute,
+			Tuner:       batchTuner,
 		}
 		log.Printf("LLM clustering enabled with model %s", cfg.VibeRouterModel)
 	}
 
-	pipeline, err := radar.NewPipeline(sources, clusterer, radar.DefaultScorer())
+	scorer := radar.DefaultScorer()
+	scorer.NoiseCap = cfg.NoiseCap
+	scorer.ExcludeNoise = cfg.NoiseMode == "exclude"
+	scorer.TagHalfLife = cfg.TagHalfLife
+
+	pipeline, err := radar.NewPipeline(sources, clusterer, scorer)
 	if err != nil {
 		log.Fatalf("init pipeline: %v", err)
 	}
 
+	switch cfg.ShadowEngine {
+	case "":
+	case "heuristic":
+		pipeline.Shadow = radar.NewShadowRunner("heuristic", radar.NewHeuristicClusterer(6*time.Hour, 0.45), radar.DefaultScorer(), cfg.ShadowSampleRate)
+	case "llm":
+		if cfg.VibeRouterAPIKey == "" {
+			log.Fatalf("shadow engine llm requires RADAR_VIBEROUTER_API_KEY")
+		}
+		pipeline.Shadow = radar.NewShadowRunner("llm", &radar.LLMClusterer{
+			Client:      llm.NewClient(cfg.VibeRouterAPIKey, llmHTTP),
+			Model:       cfg.VibeRouterModel,
+			Temperature: cfg.LLMTemperature,
+			TopP:        cfg.LLMTopP,
+			MaxTokens:   cfg.LLMMaxTokens,
+			MaxItems:    cfg.LLMMaxItems,
+			CacheTTL:    2 * time.Minute,
+			Tuner:       batchTuner,
+		}, radar.DefaultScorer(), cfg.ShadowSampleRate)
+	default:
+		log.Fatalf("unknown RADAR_SHADOW_ENGINE %q", cfg.ShadowEngine)
+	}
+	if pipeline.Shadow != nil {
+		log.Printf("Shadow clustering enabled with engine %s at %.0f%% sampling", cfg.ShadowEngine, cfg.ShadowSampleRate*100)
+	}
+
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	go state.RunJanitor(bgCtx, 10*time.Minute, log.Printf)
+
 	server := transporthttp.NewServer(pipeline, cfg, ingestSource)
+	server.EnableIdempotency(state, cfg.IdempotencyTTL)
+	server.EnableEditorial(editorial.NewBoard(state))
+	if cfg.VibeRouterAPIKey != "" && batchTuner != nil {
+		server.EnableBatchStats(batchTuner)
+	}
+	if replay != nil {
+		server.EnableReplay(replay)
+	}
 
 	// добавляем CORS и логирование
 	httpServer := &http.Server{
@@ -76,6 +171,7 @@ func main() {
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 	sig := <-sigCh
 	log.Printf("signal received: %s, shutting down", sig)
+	stopBackground()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -107,7 +203,7 @@ func withCORS(next http.Handler) http.Handler {
 		// Разрешаем фронт получать ответы
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Idempotency-Key, X-API-Key")
 		w.Header().Set("Access-Control-Allow-Credentials", "true")
 
 		// Если это preflight-запрос, сразу отвечаем
@@ -5,3 +5,8 @@ go 1.21
 require github.com/google/uuid v1.6.0
 
 require github.com/joho/godotenv v1.5.1
+
+require (
+	golang.org/x/net v0.35.0
+	golang.org/x/text v0.22.0 // indirect
+)
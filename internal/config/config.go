@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -17,8 +18,29 @@ type Config struct {
 	VibeRouterAPIKey string
 	VibeRouterModel  string
 	LLMTemperature   float64
+	LLMTopP          float64
 	LLMMaxTokens     int
 	LLMMaxItems      int
+	LLMMinItems      int
+	LLMForgetRate    float64
+	ReplayDataPath   string
+	ReplayStart      time.Time
+	ReplaySpeed      float64
+	ShadowEngine     string
+	ShadowSampleRate float64
+	StatePath        string
+	IdempotencyTTL   time.Duration
+	NoiseMode        string
+	NoiseCap         float64
+	HTTPProxy        string
+	HTTPSProxy       string
+	CABundle         string
+	TLSInsecure      bool
+	TimestampZone    *time.Location
+	StrictTimestamps bool
+	IngestMaxAge     time.Duration
+	IngestStaleMode  string
+	TagHalfLife      time.Duration
 }
 
 // FromEnv creates a configuration instance sourced from environment variables.
@@ -33,8 +55,24 @@ func FromEnv() (Config, error) {
 		VibeRouterAPIKey: getEnv("RADAR_VIBEROUTER_API_KEY", ""),
 		VibeRouterModel:  getEnv("RADAR_VIBEROUTER_MODEL", "gemini-2.5-flash"),
 		LLMTemperature:   0.2,
+		LLMTopP:          0.9,
 		LLMMaxTokens:     1024,
 		LLMMaxItems:      40,
+		LLMMinItems:      10,
+		LLMForgetRate:    0.05,
+		ReplayDataPath:   getEnv("RADAR_REPLAY_DATA", ""),
+		ReplaySpeed:      60,
+		ShadowEngine:     getEnv("RADAR_SHADOW_ENGINE", ""),
+		ShadowSampleRate: 0.1,
+		StatePath:        getEnv("RADAR_STATE_PATH", ""),
+		IdempotencyTTL:   24 * time.Hour,
+		NoiseMode:        getEnv("RADAR_NOISE_MODE", "cap"),
+		NoiseCap:         0.35,
+		HTTPProxy:        getEnv("RADAR_HTTP_PROXY", ""),
+		HTTPSProxy:       getEnv("RADAR_HTTPS_PROXY", ""),
+		CABundle:         getEnv("RADAR_CA_BUNDLE", ""),
+		TimestampZone:    time.UTC,
+		IngestStaleMode:  getEnv("RADAR_INGEST_STALE_MODE", "reject"),
 	}
 
 	if topK := os.Getenv("RADAR_TOP_K"); topK != "" {
@@ -57,6 +95,12 @@ func FromEnv() (Config, error) {
 		}
 	}
 
+	if topP := os.Getenv("RADAR_LLM_TOP_P"); topP != "" {
+		if _, err := fmt.Sscanf(topP, "%f", &cfg.LLMTopP); err != nil {
+			return Config{}, fmt.Errorf("parse RADAR_LLM_TOP_P: %w", err)
+		}
+	}
+
 	if tokens := os.Getenv("RADAR_LLM_MAX_TOKENS"); tokens != "" {
 		if _, err := fmt.Sscanf(tokens, "%d", &cfg.LLMMaxTokens); err != nil {
 			return Config{}, fmt.Errorf("parse RADAR_LLM_MAX_TOKENS: %w", err)
@@ -69,6 +113,104 @@ func FromEnv() (Config, error) {
 		}
 	}
 
+	if minItems := os.Getenv("RADAR_LLM_MIN_ITEMS"); minItems != "" {
+		if _, err := fmt.Sscanf(minItems, "%d", &cfg.LLMMinItems); err != nil {
+			return Config{}, fmt.Errorf("parse RADAR_LLM_MIN_ITEMS: %w", err)
+		}
+	}
+
+	if rate := os.Getenv("RADAR_LLM_FORGET_THRESHOLD"); rate != "" {
+		if _, err := fmt.Sscanf(rate, "%f", &cfg.LLMForgetRate); err != nil {
+			return Config{}, fmt.Errorf("parse RADAR_LLM_FORGET_THRESHOLD: %w", err)
+		}
+	}
+
+	if start := os.Getenv("RADAR_REPLAY_START"); start != "" {
+		ts, err := time.Parse(time.RFC3339, start)
+		if err != nil {
+			return Config{}, fmt.Errorf("parse RADAR_REPLAY_START: %w", err)
+		}
+		cfg.ReplayStart = ts
+	}
+
+	if speed := os.Getenv("RADAR_REPLAY_SPEED"); speed != "" {
+		if _, err := fmt.Sscanf(speed, "%f", &cfg.ReplaySpeed); err != nil {
+			return Config{}, fmt.Errorf("parse RADAR_REPLAY_SPEED: %w", err)
+		}
+	}
+
+	if rate := os.Getenv("RADAR_SHADOW_SAMPLE_RATE"); rate != "" {
+		if _, err := fmt.Sscanf(rate, "%f", &cfg.ShadowSampleRate); err != nil {
+			return Config{}, fmt.Errorf("parse RADAR_SHADOW_SAMPLE_RATE: %w", err)
+		}
+	}
+
+	if ttl := os.Getenv("RADAR_IDEMPOTENCY_TTL_H"); ttl != "" {
+		var hours int
+		if _, err := fmt.Sscanf(ttl, "%d", &hours); err != nil {
+			return Config{}, fmt.Errorf("parse RADAR_IDEMPOTENCY_TTL_H: %w", err)
+		}
+		cfg.IdempotencyTTL = time.Duration(hours) * time.Hour
+	}
+
+	switch cfg.NoiseMode {
+	case "cap", "exclude":
+	default:
+		return Config{}, fmt.Errorf("parse RADAR_NOISE_MODE: want cap or exclude, got %q", cfg.NoiseMode)
+	}
+
+	if noiseCap := os.Getenv("RADAR_NOISE_CAP"); noiseCap != "" {
+		if _, err := fmt.Sscanf(noiseCap, "%f", &cfg.NoiseCap); err != nil {
+			return Config{}, fmt.Errorf("parse RADAR_NOISE_CAP: %w", err)
+		}
+	}
+
+	if insecure := os.Getenv("RADAR_TLS_INSECURE_SKIP_VERIFY"); insecure != "" {
+		parsed, err := strconv.ParseBool(insecure)
+		if err != nil {
+			return Config{}, fmt.Errorf("parse RADAR_TLS_INSECURE_SKIP_VERIFY: %w", err)
+		}
+		cfg.TLSInsecure = parsed
+	}
+
+	if maxAge := os.Getenv("RADAR_INGEST_MAX_AGE_H"); maxAge != "" {
+		var hours int
+		if _, err := fmt.Sscanf(maxAge, "%d", &hours); err != nil {
+			return Config{}, fmt.Errorf("parse RADAR_INGEST_MAX_AGE_H: %w", err)
+		}
+		cfg.IngestMaxAge = time.Duration(hours) * time.Hour
+	}
+
+	switch cfg.IngestStaleMode {
+	case "reject", "flag":
+	default:
+		return Config{}, fmt.Errorf("parse RADAR_INGEST_STALE_MODE: want reject or flag, got %q", cfg.IngestStaleMode)
+	}
+
+	if halfLife := os.Getenv("RADAR_TAG_HALF_LIFE_H"); halfLife != "" {
+		var hours float64
+		if _, err := fmt.Sscanf(halfLife, "%f", &hours); err != nil {
+			return Config{}, fmt.Errorf("parse RADAR_TAG_HALF_LIFE_H: %w", err)
+		}
+		cfg.TagHalfLife = time.Duration(hours * float64(time.Hour))
+	}
+
+	if zone := os.Getenv("RADAR_TIMESTAMP_ZONE"); zone != "" {
+		loc, err := time.LoadLocation(zone)
+		if err != nil {
+			return Config{}, fmt.Errorf("parse RADAR_TIMESTAMP_ZONE: %w", err)
+		}
+		cfg.TimestampZone = loc
+	}
+
+	if strict := os.Getenv("RADAR_STRICT_TIMESTAMPS"); strict != "" {
+		parsed, err := strconv.ParseBool(strict)
+		if err != nil {
+			return Config{}, fmt.Errorf("parse RADAR_STRICT_TIMESTAMPS: %w", err)
+		}
+		cfg.StrictTimestamps = parsed
+	}
+
 	return cfg, nil
 }
 
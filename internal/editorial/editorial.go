@@ -0,0 +1,147 @@
+// Package editorial tracks the triage state editors assign to radar events.
+package editorial
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"finamhackbackend/internal/store"
+)
+
+const keyPrefix = "editorial:"
+
+// State is a step of the editorial workflow.
+type State string
+
+const (
+	StateNew       State = "new"
+	StateInReview  State = "in_review"
+	StateApproved  State = "approved"
+	StatePublished State = "published"
+	StateRejected  State = "rejected"
+)
+
+var (
+	// ErrInvalidState is returned for a state outside the workflow.
+	ErrInvalidState = errors.New("editorial: unknown state")
+	// ErrInvalidTransition is returned when the workflow does not allow the move.
+	ErrInvalidTransition = errors.New("editorial: transition not allowed")
+)
+
+// transitions lists the states reachable from each state. Re-applying the
+// current state is always allowed so editors can update the note.
+var transitions = map[State][]State{
+	StateNew:       {StateInReview, StateRejected},
+	StateInReview:  {StateNew, StateApproved, StateRejected},
+	StateApproved:  {StateInReview, StatePublished, StateRejected},
+	StatePublished: {},
+	StateRejected:  {StateNew, StateInReview},
+}
+
+// ParseState validates a state name.
+func ParseState(raw string) (State, error) {
+	state := State(strings.ToLower(strings.TrimSpace(raw)))
+	if _, ok := transitions[state]; !ok {
+		return "", fmt.Errorf("%w %q", ErrInvalidState, raw)
+	}
+	return state, nil
+}
+
+// Record is the editorial state of one event.
+type Record struct {
+	EventID   string    `json:"event_id"`
+	State     State     `json:"state"`
+	Note      string    `json:"note,omitempty"`
+	Editor    string    `json:"editor,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Board persists editorial records keyed by radar event ID.
+type Board struct {
+	mu    sync.Mutex
+	store *store.Store
+	now   func() time.Time
+}
+
+// NewBoard returns a board backed by st.
+func NewBoard(st *store.Store) *Board {
+	return &Board{store: st, now: time.Now}
+}
+
+// Get returns the record for eventID, if any.
+func (b *Board) Get(eventID string) (Record, bool, error) {
+	var rec Record
+	ok, err := b.store.Get(keyPrefix+eventID, &rec)
+	return rec, ok, err
+}
+
+// Set moves eventID to state. Events without a record are treated as new.
+func (b *Board) Set(eventID string, state State, note, editor string) (Record, error) {
+	if _, ok := transitions[state]; !ok {
+		return Record{}, fmt.Errorf("%w %q", ErrInvalidState, state)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	current, ok, err := b.Get(eventID)
+	if err != nil {
+		return Record{}, err
+	}
+	from := StateNew
+	if ok {
+		from = current.State
+	}
+	if !allowed(from, state) {
+		return Record{}, fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, from, state)
+	}
+
+	rec := Record{
+		EventID:   eventID,
+		State:     state,
+		Note:      strings.TrimSpace(note),
+		Editor:    strings.TrimSpace(editor),
+		UpdatedAt: b.now().UTC(),
+	}
+	if err := b.store.Put(keyPrefix+eventID, rec, 0); err != nil {
+		return Record{}, err
+	}
+	return rec, nil
+}
+
+// List returns the records in state, or all records when state is empty,
+// most recently updated first.
+func (b *Board) List(state State) ([]Record, error) {
+	records := []Record{}
+	for _, key := range b.store.Keys(keyPrefix) {
+		var rec Record
+		ok, err := b.store.Get(key, &rec)
+		if err != nil {
+			return nil, err
+		}
+		if !ok || (state != "" && rec.State != state) {
+			continue
+		}
+		records = append(records, rec)
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].UpdatedAt.After(records[j].UpdatedAt)
+	})
+	return records, nil
+}
+
+func allowed(from, to State) bool {
+	if from == to {
+		return true
+	}
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
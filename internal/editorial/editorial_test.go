@@ -0,0 +1,78 @@
+package editorial
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"finamhackbackend/internal/store"
+)
+
+func TestBoardValidatesTransitions(t *testing.T) {
+	st, err := store.Open("")
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	board := NewBoard(st)
+
+	if _, err := board.Set("evt_1", StatePublished, "", ""); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("publishing a new event should fail, got %v", err)
+	}
+	for _, state := range []State{StateInReview, StateApproved, StateApproved, StatePublished} {
+		if _, err := board.Set("evt_1", state, "", "anna"); err != nil {
+			t.Fatalf("set %s: %v", state, err)
+		}
+	}
+	if _, err := board.Set("evt_1", StateInReview, "", ""); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("published events should be final, got %v", err)
+	}
+	if _, err := board.Set("evt_2", State("archived"), "", ""); !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("expected unknown state error, got %v", err)
+	}
+	if _, err := ParseState(" In_Review "); err != nil {
+		t.Fatalf("parse state: %v", err)
+	}
+}
+
+func TestBoardPersistsAcrossRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	st, err := store.Open(path)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	board := NewBoard(st)
+	if _, err := board.Set("evt_1", StateInReview, "check numbers", "anna"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if _, err := board.Set("evt_2", StateRejected, "duplicate", "boris"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	reopened, err := store.Open(path)
+	if err != nil {
+		t.Fatalf("reopen store: %v", err)
+	}
+	restarted := NewBoard(reopened)
+	rec, ok, err := restarted.Get("evt_1")
+	if err != nil || !ok {
+		t.Fatalf("expected persisted record, got ok=%v err=%v", ok, err)
+	}
+	if rec.State != StateInReview || rec.Note != "check numbers" || rec.Editor != "anna" {
+		t.Fatalf("unexpected record: %+v", rec)
+	}
+
+	rejected, err := restarted.List(StateRejected)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(rejected) != 1 || rejected[0].EventID != "evt_2" {
+		t.Fatalf("expected only evt_2 to be rejected, got %+v", rejected)
+	}
+	all, err := restarted.List("")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(all))
+	}
+}
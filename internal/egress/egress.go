@@ -0,0 +1,103 @@
+// Package egress builds the outbound HTTP transport shared by the LLM client and
+// HTTP-backed sources, so proxy and TLS settings are configured in one place.
+package egress
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// Options configures outbound connections. Empty proxies fall back to the
+// standard HTTP_PROXY/HTTPS_PROXY environment; NO_PROXY applies to explicit
+// proxies as well, and loopback targets are never proxied.
+type Options struct {
+	// HTTPProxy and HTTPSProxy are proxy URLs used for plain and TLS targets.
+	// Credentials in the URL userinfo are sent as Proxy-Authorization.
+	HTTPProxy  string
+	HTTPSProxy string
+	// CABundle is a PEM file whose certificates are trusted in addition to the
+	// system roots.
+	CABundle string
+	// InsecureSkipVerify disables certificate verification; for lab use only.
+	InsecureSkipVerify bool
+}
+
+// NewTransport returns a transport applying opts. It fails when a proxy URL is
+// malformed or the CA bundle cannot be read or holds no certificates.
+func NewTransport(opts Options) (*http.Transport, error) {
+	httpProxy, err := parseProxy("http proxy", opts.HTTPProxy)
+	if err != nil {
+		return nil, err
+	}
+	httpsProxy, err := parseProxy("https proxy", opts.HTTPSProxy)
+	if err != nil {
+		return nil, err
+	}
+
+	proxyConfig := httpproxy.FromEnvironment()
+	if httpProxy != nil {
+		proxyConfig.HTTPProxy = httpProxy.String()
+	}
+	if httpsProxy != nil {
+		proxyConfig.HTTPSProxy = httpsProxy.String()
+	}
+	proxyFunc := proxyConfig.ProxyFunc()
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: opts.InsecureSkipVerify}
+	if opts.CABundle != "" {
+		pool, err := loadCABundle(opts.CABundle)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// NewClient wraps transport in a client with the given timeout. HTTP-backed
+// sources should obtain their client here rather than using http.DefaultClient.
+func NewClient(transport http.RoundTripper, timeout time.Duration) *http.Client {
+	return &http.Client{Transport: transport, Timeout: timeout}
+}
+
+func parseProxy(label, raw string) (*url.URL, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	proxyURL, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("egress: parse %s: %w", label, err)
+	}
+	if proxyURL.Scheme == "" || proxyURL.Host == "" {
+		return nil, fmt.Errorf("egress: parse %s: want scheme://host[:port], got %q", label, raw)
+	}
+	return proxyURL, nil
+}
+
+func loadCABundle(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("egress: read CA bundle: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("egress: CA bundle %s contains no PEM certificates", path)
+	}
+	return pool, nil
+}
@@ -0,0 +1,228 @@
+package egress
+
+import (
+	"encoding/pem"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func writeCABundle(t *testing.T, srv *httptest.Server) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	block := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(path, block, 0o600); err != nil {
+		t.Fatalf("write bundle: %v", err)
+	}
+	return path
+}
+
+func TestTransportTrustsCABundle(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	plain, err := NewTransport(Options{})
+	if err != nil {
+		t.Fatalf("transport: %v", err)
+	}
+	if _, err := NewClient(plain, 5*time.Second).Get(srv.URL); err == nil {
+		t.Fatalf("expected verification failure without the CA bundle")
+	}
+
+	trusted, err := NewTransport(Options{CABundle: writeCABundle(t, srv)})
+	if err != nil {
+		t.Fatalf("transport: %v", err)
+	}
+	resp, err := NewClient(trusted, 5*time.Second).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("get with CA bundle: %v", err)
+	}
+	resp.Body.Close()
+
+	insecure, err := NewTransport(Options{InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatalf("transport: %v", err)
+	}
+	resp, err = NewClient(insecure, 5*time.Second).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("get with verification disabled: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestTransportRoutesThroughProxyWithAuth(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		targets []string
+		auth    string
+	)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		targets = append(targets, r.URL.String())
+		auth = r.Header.Get("Proxy-Authorization")
+		mu.Unlock()
+		_, _ = io.WriteString(w, "proxied")
+	}))
+	defer proxy.Close()
+
+	proxyURL := strings.Replace(proxy.URL, "http://", "http://user:secret@", 1)
+	transport, err := NewTransport(Options{HTTPProxy: proxyURL})
+	if err != nil {
+		t.Fatalf("transport: %v", err)
+	}
+	resp, err := NewClient(transport, 5*time.Second).Get("http://upstream.invalid/feed")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if string(body) != "proxied" {
+		t.Fatalf("expected response from proxy, got %q", body)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(targets) != 1 || targets[0] != "http://upstream.invalid/feed" {
+		t.Fatalf("proxy saw %v", targets)
+	}
+	if auth != "Basic dXNlcjpzZWNyZXQ=" {
+		t.Fatalf("expected basic proxy auth from userinfo, got %q", auth)
+	}
+}
+
+// connectProxy tunnels every CONNECT request to target, whatever host the
+// client asked for, and records the requested hosts and credentials.
+func connectProxy(t *testing.T, target string) (*httptest.Server, func() ([]string, string)) {
+	t.Helper()
+	var (
+		mu    sync.Mutex
+		hosts []string
+		auth  string
+	)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "CONNECT only", http.StatusMethodNotAllowed)
+			return
+		}
+		mu.Lock()
+		hosts = append(hosts, r.Host)
+		auth = r.Header.Get("Proxy-Authorization")
+		mu.Unlock()
+
+		upstream, err := net.Dial("tcp", target)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		client, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			upstream.Close()
+			return
+		}
+		_, _ = io.WriteString(client, "HTTP/1.1 200 Connection established\r\n\r\n")
+		go func() {
+			_, _ = io.Copy(upstream, client)
+			upstream.Close()
+		}()
+		_, _ = io.Copy(client, upstream)
+		client.Close()
+	}))
+	return proxy, func() ([]string, string) {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), hosts...), auth
+	}
+}
+
+func TestTransportTunnelsHTTPSThroughProxy(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "secure "+r.Host)
+	}))
+	defer srv.Close()
+	proxy, seen := connectProxy(t, srv.Listener.Addr().String())
+	defer proxy.Close()
+
+	transport, err := NewTransport(Options{
+		HTTPSProxy: strings.Replace(proxy.URL, "http://", "http://user:secret@", 1),
+		CABundle:   writeCABundle(t, srv),
+	})
+	if err != nil {
+		t.Fatalf("transport: %v", err)
+	}
+	// The test certificate is issued for example.com, so TLS verification runs
+	// against the real hostname while the proxy routes to the local server.
+	resp, err := NewClient(transport, 5*time.Second).Get("https://example.com/feed")
+	if err != nil {
+		t.Fatalf("get through CONNECT proxy: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if string(body) != "secure example.com" {
+		t.Fatalf("expected response from TLS target, got %q", body)
+	}
+	hosts, auth := seen()
+	if len(hosts) != 1 || hosts[0] != "example.com:443" {
+		t.Fatalf("proxy saw CONNECT to %v", hosts)
+	}
+	if auth != "Basic dXNlcjpzZWNyZXQ=" {
+		t.Fatalf("expected basic proxy auth on CONNECT, got %q", auth)
+	}
+}
+
+func TestTransportHonoursNoProxyWithExplicitProxy(t *testing.T) {
+	t.Setenv("NO_PROXY", "internal.example,.corp.example")
+	t.Setenv("no_proxy", "")
+
+	transport, err := NewTransport(Options{HTTPProxy: "http://proxy.example:3128", HTTPSProxy: "http://proxy.example:3128"})
+	if err != nil {
+		t.Fatalf("transport: %v", err)
+	}
+	cases := map[string]bool{
+		"https://api.example/v1":          true,
+		"http://internal.example/feed":    false,
+		"https://news.corp.example/feed":  false,
+		"http://127.0.0.1:8080/local":     false,
+		"https://internal.example.org/v1": true,
+	}
+	for target, wantProxy := range cases {
+		req, err := http.NewRequest(http.MethodGet, target, nil)
+		if err != nil {
+			t.Fatalf("request: %v", err)
+		}
+		proxyURL, err := transport.Proxy(req)
+		if err != nil {
+			t.Fatalf("%s: proxy: %v", target, err)
+		}
+		if got := proxyURL != nil; got != wantProxy {
+			t.Errorf("%s: proxied=%v, want %v", target, got, wantProxy)
+		}
+	}
+}
+
+func TestNewTransportValidatesOptions(t *testing.T) {
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(empty, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	cases := map[string]Options{
+		"missing bundle": {CABundle: filepath.Join(t.TempDir(), "missing.pem")},
+		"bad bundle":     {CABundle: empty},
+		"bad proxy":      {HTTPSProxy: "proxy.local:3128"},
+	}
+	for name, opts := range cases {
+		if _, err := NewTransport(opts); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
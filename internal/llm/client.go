@@ -52,6 +52,7 @@ type Client struct {
 	baseURL    string
 	apiKey     string
 	httpClient *http.Client
+	policies   map[string]ModelPolicy
 }
 
 // NewClient constructs a client with sane defaults.
@@ -62,6 +63,7 @@ func NewClient(apiKey string, opts ...func(*Client)) *Client {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		policies: DefaultPolicies,
 	}
 	for _, opt := range opts {
 		opt(c)
@@ -85,11 +87,13 @@ func WithBaseURL(url string) func(*Client) {
 	}
 }
 
-// ChatCompletion executes a chat completion request against VibeRouter.
+// ChatCompletion executes a chat completion request against VibeRouter. Parameters
+// are first normalized against the model policy table.
 func (c *Client) ChatCompletion(ctx context.Context, req ChatCompletionRequest) (*ChatCompletionResponse, error) {
 	if c.apiKey == "" {
 		return nil, fmt.Errorf("llm: missing API key")
 	}
+	req = NormalizeRequest(req, c.policies)
 
 	body, err := json.Marshal(req)
 	if err != nil {
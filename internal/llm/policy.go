@@ -0,0 +1,97 @@
+package llm
+
+import (
+	"log"
+	"strings"
+)
+
+// ModelPolicy describes the request parameter limits a model accepts.
+type ModelPolicy struct {
+	// ContextWindow is the total number of tokens (prompt + completion) the model accepts.
+	ContextWindow int
+	// MaxOutputTokens caps max_tokens regardless of the remaining context.
+	MaxOutputTokens int
+	// TopPWithTemperature reports whether top_p may be sent together with a non-zero temperature.
+	TopPWithTemperature bool
+}
+
+// DefaultPolicies lists known models. Keys match either the exact model ID or,
+// failing that, the longest key that prefixes it (e.g. "claude-" for all Claude models).
+var DefaultPolicies = map[string]ModelPolicy{
+	"gemini-2.5-flash": {ContextWindow: 1048576, MaxOutputTokens: 65536, TopPWithTemperature: true},
+	"gemini-2.5-pro":   {ContextWindow: 1048576, MaxOutputTokens: 65536, TopPWithTemperature: true},
+	"gpt-4o":           {ContextWindow: 128000, MaxOutputTokens: 16384, TopPWithTemperature: true},
+	"gpt-4o-mini":      {ContextWindow: 128000, MaxOutputTokens: 16384, TopPWithTemperature: true},
+	"claude-":          {ContextWindow: 200000, MaxOutputTokens: 8192, TopPWithTemperature: false},
+	"llama-3.1-8b":     {ContextWindow: 8192, MaxOutputTokens: 2048, TopPWithTemperature: true},
+	"mistral-7b":       {ContextWindow: 8192, MaxOutputTokens: 2048, TopPWithTemperature: true},
+}
+
+// WithPolicies overrides the model policy table consulted before each request.
+func WithPolicies(policies map[string]ModelPolicy) func(*Client) {
+	return func(c *Client) {
+		c.policies = policies
+	}
+}
+
+// LookupPolicy returns the policy for model from policies.
+func LookupPolicy(policies map[string]ModelPolicy, model string) (ModelPolicy, bool) {
+	if policy, ok := policies[model]; ok {
+		return policy, true
+	}
+	var best string
+	for key := range policies {
+		if strings.HasPrefix(model, key) && len(key) > len(best) {
+			best = key
+		}
+	}
+	if best == "" {
+		return ModelPolicy{}, false
+	}
+	return policies[best], true
+}
+
+// NormalizeRequest clamps or strips parameters the model's policy does not allow.
+// Requests for models without a policy are returned unchanged.
+func NormalizeRequest(req ChatCompletionRequest, policies map[string]ModelPolicy) ChatCompletionRequest {
+	policy, ok := LookupPolicy(policies, req.Model)
+	if !ok {
+		return req
+	}
+
+	if req.MaxTokens > 0 {
+		limit := req.MaxTokens
+		if policy.MaxOutputTokens > 0 && limit > policy.MaxOutputTokens {
+			limit = policy.MaxOutputTokens
+		}
+		if policy.ContextWindow > 0 {
+			remaining := policy.ContextWindow - EstimateTokens(req.Messages)
+			if remaining <= 0 {
+				log.Printf("llm: prompt for %s exceeds the %d-token context window", req.Model, policy.ContextWindow)
+			} else if limit > remaining {
+				limit = remaining
+			}
+		}
+		if limit != req.MaxTokens {
+			log.Printf("llm: clamped max_tokens for %s from %d to %d", req.Model, req.MaxTokens, limit)
+			req.MaxTokens = limit
+		}
+	}
+
+	if req.TopP != 0 && req.Temperature != 0 && !policy.TopPWithTemperature {
+		log.Printf("llm: dropped top_p for %s: not allowed together with temperature", req.Model)
+		req.TopP = 0
+	}
+
+	return req
+}
+
+// EstimateTokens roughly approximates the prompt size in tokens (about four
+// characters per token plus per-message framing).
+func EstimateTokens(messages []Message) int {
+	var total int
+	for _, msg := range messages {
+		total += 4 + (len(msg.Role)+len(msg.Content)+3)/4
+	}
+	return total
+}
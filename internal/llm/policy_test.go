@@ -0,0 +1,89 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeRequestClampsMaxTokensToContext(t *testing.T) {
+	policies := map[string]ModelPolicy{
+		"tiny-model": {ContextWindow: 2048, MaxOutputTokens: 4096, TopPWithTemperature: true},
+	}
+	prompt := strings.Repeat("a", 6000)
+	req := ChatCompletionRequest{
+		Model:     "tiny-model",
+		Messages:  []Message{{Role: "user", Content: prompt}},
+		MaxTokens: 1024,
+	}
+
+	got := NormalizeRequest(req, policies)
+
+	want := 2048 - EstimateTokens(req.Messages)
+	if got.MaxTokens != want {
+		t.Fatalf("expected max_tokens clamped to %d, got %d", want, got.MaxTokens)
+	}
+}
+
+func TestNormalizeRequestClampsMaxTokensToOutputLimit(t *testing.T) {
+	got := NormalizeRequest(ChatCompletionRequest{Model: "llama-3.1-8b-instant", MaxTokens: 4096}, DefaultPolicies)
+	if got.MaxTokens != 2048 {
+		t.Fatalf("expected max_tokens clamped to 2048, got %d", got.MaxTokens)
+	}
+}
+
+func TestNormalizeRequestStripsTopPWhenForbidden(t *testing.T) {
+	req := ChatCompletionRequest{Model: "claude-sonnet", Temperature: 0.2, TopP: 0.9, MaxTokens: 512}
+
+	got := NormalizeRequest(req, DefaultPolicies)
+	if got.TopP != 0 {
+		t.Fatalf("expected top_p stripped, got %v", got.TopP)
+	}
+	if got.Temperature != 0.2 || got.MaxTokens != 512 {
+		t.Fatalf("unexpected changes to other fields: %+v", got)
+	}
+
+	req.Temperature = 0
+	if got := NormalizeRequest(req, DefaultPolicies); got.TopP != 0.9 {
+		t.Fatalf("top_p alone should be kept, got %v", got.TopP)
+	}
+}
+
+func TestNormalizeRequestLeavesUnknownModels(t *testing.T) {
+	req := ChatCompletionRequest{Model: "unknown", Temperature: 0.2, TopP: 0.9, MaxTokens: 1 << 20}
+	if got := NormalizeRequest(req, DefaultPolicies); got.MaxTokens != req.MaxTokens || got.TopP != req.TopP {
+		t.Fatalf("unknown model request should be unchanged, got %+v", got)
+	}
+}
+
+func TestClientNormalizesBeforeSending(t *testing.T) {
+	var sent map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"choices":[]}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("key", WithBaseURL(srv.URL))
+	_, err := client.ChatCompletion(context.Background(), ChatCompletionRequest{
+		Model:       "claude-haiku",
+		Temperature: 0.3,
+		TopP:        0.9,
+		MaxTokens:   20000,
+	})
+	if err != nil {
+		t.Fatalf("chat completion: %v", err)
+	}
+
+	if _, ok := sent["top_p"]; ok {
+		t.Errorf("top_p should not be sent, got %v", sent["top_p"])
+	}
+	if sent["max_tokens"] != float64(8192) {
+		t.Errorf("expected max_tokens 8192, got %v", sent["max_tokens"])
+	}
+}
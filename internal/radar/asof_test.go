@@ -0,0 +1,180 @@
+package radar
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestPipelineAsOfSkipsLaterIngests(t *testing.T) {
+	base := time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)
+	asOf := base.Add(2 * time.Hour)
+
+	ingest := NewIngestSource("ingest")
+	ingest.Add(NewsItem{ID: "early", Headline: "Early", Source: "reuters", PublishedAt: base, IngestedAt: base.Add(time.Minute), Tickers: []string{"SBER"}})
+	ingest.Add(NewsItem{ID: "late", Headline: "Late", Source: "reuters", PublishedAt: base.Add(time.Hour), IngestedAt: asOf.Add(time.Minute), Tickers: []string{"GAZP"}})
+	static := staticSource{name: "static", items: []NewsItem{
+		{ID: "static", Headline: "Static", Source: "bloomberg", PublishedAt: base, Tickers: []string{"LKOH"}},
+	}}
+
+	sources, err := NewSourceRegistry(ingest, static)
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+	pipeline, err := NewPipeline(sources, DefaultClusterer(), DefaultScorer())
+	if err != nil {
+		t.Fatalf("pipeline: %v", err)
+	}
+
+	events, meta, err := pipeline.RunWithMeta(context.Background(), QueryParams{From: base.Add(-time.Hour), To: asOf, Limit: 10, AsOf: asOf})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	seen := make(map[string]bool)
+	for _, event := range events {
+		for _, src := range event.Sources {
+			seen[src.Title] = true
+		}
+	}
+	if !seen["Early"] || !seen["Static"] || seen["Late"] {
+		t.Fatalf("expected early and static items only, got %v", seen)
+	}
+	if len(meta.Degradations) != 2 {
+		t.Fatalf("expected archive and ingest-timestamp degradations, got %v", meta.Degradations)
+	}
+
+	_, meta, err = pipeline.RunWithMeta(context.Background(), QueryParams{From: base.Add(-time.Hour), To: asOf, Limit: 10})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(meta.Degradations) != 0 {
+		t.Fatalf("live run should not report degradations, got %v", meta.Degradations)
+	}
+}
+
+func TestIngestSourceStampsIngestedAt(t *testing.T) {
+	ingest := NewIngestSource("ingest")
+	before := time.Now().UTC()
+	stored := ingest.Add(NewsItem{ID: "n1", Headline: "Headline"})
+	if stored.IngestedAt.Before(before) {
+		t.Fatalf("expected IngestedAt to be stamped, got %s", stored.IngestedAt)
+	}
+}
+
+func TestPipelineAsOfServesVersionBeforeCorrection(t *testing.T) {
+	base := time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)
+	asOf := base.Add(2 * time.Hour)
+
+	ingest := NewIngestSource("ingest")
+	ingest.Add(NewsItem{ID: "n1", Headline: "Original", Source: "reuters", PublishedAt: base, IngestedAt: base.Add(time.Minute), Tickers: []string{"SBER"}})
+	corrected := ingest.Add(NewsItem{ID: "n1", Headline: "Corrected", Source: "reuters", PublishedAt: base, IngestedAt: asOf.Add(time.Hour), Tickers: []string{"SBER"}})
+	if !corrected.IngestedAt.Equal(base.Add(time.Minute)) || !corrected.RevisedAt.Equal(asOf.Add(time.Hour)) {
+		t.Fatalf("expected first ingest to be kept, got ingested %s revised %s", corrected.IngestedAt, corrected.RevisedAt)
+	}
+
+	sources, err := NewSourceRegistry(ingest)
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+	pipeline, err := NewPipeline(sources, DefaultClusterer(), DefaultScorer())
+	if err != nil {
+		t.Fatalf("pipeline: %v", err)
+	}
+
+	titles := func(params QueryParams) map[string]bool {
+		t.Helper()
+		events, _, err := pipeline.RunWithMeta(context.Background(), params)
+		if err != nil {
+			t.Fatalf("run: %v", err)
+		}
+		seen := make(map[string]bool)
+		for _, event := range events {
+			for _, src := range event.Sources {
+				seen[src.Title] = true
+			}
+		}
+		return seen
+	}
+
+	if seen := titles(QueryParams{From: base.Add(-time.Hour), To: asOf, Limit: 10, AsOf: asOf}); !seen["Original"] || seen["Corrected"] {
+		t.Fatalf("expected the original version at as_of, got %v", seen)
+	}
+	if seen := titles(QueryParams{From: base.Add(-time.Hour), To: asOf, Limit: 10}); !seen["Corrected"] || seen["Original"] {
+		t.Fatalf("expected the corrected version live, got %v", seen)
+	}
+}
+
+func TestIngestSourceBoundsRevisions(t *testing.T) {
+	base := time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)
+	ingest := NewIngestSource("ingest")
+
+	for i := 0; i < 5; i++ {
+		ingest.Add(NewsItem{ID: "n1", Headline: "Same", Source: "reuters", PublishedAt: base, IngestedAt: base.Add(time.Duration(i) * time.Minute), Tickers: []string{"SBER"}})
+	}
+	items, err := ingest.Fetch(context.Background(), base, base)
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if len(items) != 1 || len(items[0].Revisions) != 0 || !items[0].RevisedAt.IsZero() {
+		t.Fatalf("identical resubmissions should not add revisions, got %+v", items)
+	}
+
+	for i := 0; i < maxRevisions+5; i++ {
+		ingest.Add(NewsItem{ID: "n1", Headline: fmt.Sprintf("Edit %d", i), Source: "reuters", PublishedAt: base, IngestedAt: base.Add(time.Hour + time.Duration(i)*time.Minute)})
+	}
+	items, _ = ingest.Fetch(context.Background(), base, base)
+	if len(items) != 1 || len(items[0].Revisions) != maxRevisions {
+		t.Fatalf("expected history capped at %d, got %d", maxRevisions, len(items[0].Revisions))
+	}
+	if last := items[0].Revisions[maxRevisions-1]; last.Headline != fmt.Sprintf("Edit %d", maxRevisions+3) {
+		t.Fatalf("expected the newest superseded version to be kept, got %q", last.Headline)
+	}
+}
+
+func TestPipelineAsOfRechecksWindowForEarlierVersion(t *testing.T) {
+	base := time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)
+	asOf := base.Add(4 * time.Hour)
+
+	ingest := NewIngestSource("ingest")
+	// n1 was first published inside the window and later moved out of it; n2
+	// moved into the window only after as_of.
+	ingest.Add(NewsItem{ID: "n1", Headline: "Moved out", Source: "reuters", PublishedAt: base, IngestedAt: base, Tickers: []string{"SBER"}})
+	ingest.Add(NewsItem{ID: "n1", Headline: "Moved out", Source: "reuters", PublishedAt: base.Add(-48 * time.Hour), IngestedAt: asOf.Add(time.Hour), Tickers: []string{"SBER"}})
+	ingest.Add(NewsItem{ID: "n2", Headline: "Moved in", Source: "reuters", PublishedAt: base.Add(-48 * time.Hour), IngestedAt: base, Tickers: []string{"GAZP"}})
+	ingest.Add(NewsItem{ID: "n2", Headline: "Moved in", Source: "reuters", PublishedAt: base.Add(time.Hour), IngestedAt: asOf.Add(time.Hour), Tickers: []string{"GAZP"}})
+
+	sources, err := NewSourceRegistry(ingest)
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+	pipeline, err := NewPipeline(sources, DefaultClusterer(), DefaultScorer())
+	if err != nil {
+		t.Fatalf("pipeline: %v", err)
+	}
+
+	window := QueryParams{From: base.Add(-time.Hour), To: asOf, Limit: 10}
+	titles := func(params QueryParams) map[string]bool {
+		t.Helper()
+		events, _, err := pipeline.RunWithMeta(context.Background(), params)
+		if err != nil {
+			t.Fatalf("run: %v", err)
+		}
+		seen := make(map[string]bool)
+		for _, event := range events {
+			for _, src := range event.Sources {
+				seen[src.Title] = true
+			}
+		}
+		return seen
+	}
+
+	historical := window
+	historical.AsOf = asOf
+	if seen := titles(historical); !seen["Moved out"] || seen["Moved in"] {
+		t.Fatalf("as_of run should use the versions then in the window, got %v", seen)
+	}
+	if seen := titles(window); seen["Moved out"] || !seen["Moved in"] {
+		t.Fatalf("live run should use the current versions, got %v", seen)
+	}
+}
@@ -0,0 +1,206 @@
+package radar
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+const batchTunerKeyPrefix = "llm_batch:"
+
+// BatchStateStore persists learned batch sizes; *store.Store satisfies it.
+type BatchStateStore interface {
+	Get(key string, dst any) (bool, error)
+	Put(key string, value any, ttl time.Duration) error
+}
+
+// BatchStats reports the adaptive batch size and response quality for one model.
+type BatchStats struct {
+	Model      string  `json:"model"`
+	Size       int     `json:"size"`
+	Min        int     `json:"min"`
+	Max        int     `json:"max"`
+	ForgetRate float64 `json:"forget_rate"`
+	Samples    int     `json:"samples"`
+	Requests   int64   `json:"requests"`
+	Sent       int64   `json:"sent"`
+	Forgotten  int64   `json:"forgotten"`
+	Shrinks    int     `json:"shrinks"`
+	Grows      int     `json:"grows"`
+}
+
+// BatchTuner adapts how many items the LLM clusterer sends per request. For
+// each model it tracks the share of sent items the response forgot to place in
+// any cluster over a rolling window: above Threshold the size shrinks by
+// ShrinkFactor, and after a full window at or below half the threshold it grows
+// back by GrowStep. Learned sizes are persisted when a Store is set.
+type BatchTuner struct {
+	Min          int
+	Max          int
+	Threshold    float64
+	Window       int
+	MinSamples   int
+	ShrinkFactor float64
+	GrowStep     int
+	Store        BatchStateStore
+
+	mu     sync.Mutex
+	models map[string]*batchQuality
+}
+
+type batchSample struct {
+	sent      int
+	forgotten int
+}
+
+type batchQuality struct {
+	size      int
+	window    []batchSample
+	requests  int64
+	sent      int64
+	forgotten int64
+	shrinks   int
+	grows     int
+}
+
+// NewBatchTuner returns a tuner that starts each model at max items and never
+// goes below min. max is a hard cap, so a min above it is lowered to max. It
+// returns nil when max <= 0, which leaves requests uncapped.
+func NewBatchTuner(min, max int, threshold float64, st BatchStateStore) *BatchTuner {
+	if max <= 0 {
+		return nil
+	}
+	if min < 1 {
+		min = 1
+	}
+	if min > max {
+		min = max
+	}
+	return &BatchTuner{
+		Min:          min,
+		Max:          max,
+		Threshold:    threshold,
+		Window:       20,
+		MinSamples:   3,
+		ShrinkFactor: 0.75,
+		GrowStep:     1,
+		Store:        st,
+		models:       make(map[string]*batchQuality),
+	}
+}
+
+// Size returns the current number of items to send to model.
+func (t *BatchTuner) Size(model string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.quality(model).size
+}
+
+// Observe records one response: sent items were in the prompt and forgotten of
+// them were missing from every cluster and the noise list.
+func (t *BatchTuner) Observe(model string, sent, forgotten int) {
+	if sent <= 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	q := t.quality(model)
+	q.requests++
+	q.sent += int64(sent)
+	q.forgotten += int64(forgotten)
+	q.window = append(q.window, batchSample{sent: sent, forgotten: forgotten})
+	if window := t.Window; window > 0 && len(q.window) > window {
+		q.window = append([]batchSample(nil), q.window[len(q.window)-window:]...)
+	}
+
+	rate := forgetRate(q.window)
+	size := q.size
+	switch {
+	case len(q.window) >= t.MinSamples && rate > t.Threshold && q.size > t.Min:
+		size = int(float64(q.size) * t.ShrinkFactor)
+		if size >= q.size {
+			size = q.size - 1
+		}
+		if size < t.Min {
+			size = t.Min
+		}
+		q.shrinks++
+	case len(q.window) >= t.Window && rate <= t.Threshold/2 && q.size < t.Max:
+		size = q.size + t.GrowStep
+		if size > t.Max {
+			size = t.Max
+		}
+		q.grows++
+	default:
+		return
+	}
+
+	log.Printf("BatchTuner %s: forget rate %.3f over %d requests, batch size %d -> %d", model, rate, len(q.window), q.size, size)
+	q.size = size
+	// measure the new size on its own responses
+	q.window = nil
+	if t.Store != nil {
+		if err := t.Store.Put(batchTunerKeyPrefix+model, size, 0); err != nil {
+			log.Printf("BatchTuner %s: persist size: %v", model, err)
+		}
+	}
+}
+
+// Stats returns per-model quality statistics ordered by model name.
+func (t *BatchTuner) Stats() []BatchStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := make([]BatchStats, 0, len(t.models))
+	for model, q := range t.models {
+		stats = append(stats, BatchStats{
+			Model:      model,
+			Size:       q.size,
+			Min:        t.Min,
+			Max:        t.Max,
+			ForgetRate: roundTo(forgetRate(q.window), 3),
+			Samples:    len(q.window),
+			Requests:   q.requests,
+			Sent:       q.sent,
+			Forgotten:  q.forgotten,
+			Shrinks:    q.shrinks,
+			Grows:      q.grows,
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Model < stats[j].Model })
+	return stats
+}
+
+// quality returns the state for model, restoring a persisted size on first use;
+// callers must hold t.mu.
+func (t *BatchTuner) quality(model string) *batchQuality {
+	if q, ok := t.models[model]; ok {
+		return q
+	}
+	q := &batchQuality{size: t.Max}
+	if t.Store != nil {
+		var size int
+		ok, err := t.Store.Get(batchTunerKeyPrefix+model, &size)
+		if err != nil {
+			log.Printf("BatchTuner %s: load size: %v", model, err)
+		} else if ok && size >= t.Min && size <= t.Max {
+			q.size = size
+		}
+	}
+	t.models[model] = q
+	return q
+}
+
+func forgetRate(window []batchSample) float64 {
+	var sent, forgotten int
+	for _, sample := range window {
+		sent += sample.sent
+		forgotten += sample.forgotten
+	}
+	if sent == 0 {
+		return 0
+	}
+	return float64(forgotten) / float64(sent)
+}
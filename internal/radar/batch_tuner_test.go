@@ -0,0 +1,186 @@
+package radar
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"finamhackbackend/internal/llm"
+	"finamhackbackend/internal/store"
+)
+
+func batchItems(n int) []NewsItem {
+	base := time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)
+	items := make([]NewsItem, n)
+	for idx := range items {
+		items[idx] = NewsItem{
+			ID:          fmt.Sprintf("n%02d", idx),
+			Headline:    fmt.Sprintf("Headline %d", idx),
+			Source:      "Reuters",
+			PublishedAt: base.Add(time.Duration(idx) * time.Minute),
+		}
+	}
+	return items
+}
+
+// clusterResponse places the given ids into a single cluster.
+func clusterResponse(ids []string) string {
+	return fmt.Sprintf(`{"clusters":[{"id":"event_1","news_ids":["%s"]}]}`, strings.Join(ids, `","`))
+}
+
+var promptIDPattern = regexp.MustCompile(`"id": "([^"]+)"`)
+
+// forgetfulChatClient places at most keep of the prompt's items in one cluster,
+// mimicking a model that drops IDs from long prompts; keep <= 0 places all.
+type forgetfulChatClient struct {
+	keep  int
+	calls int
+}
+
+func (f *forgetfulChatClient) ChatCompletion(ctx context.Context, req llm.ChatCompletionRequest) (*llm.ChatCompletionResponse, error) {
+	f.calls++
+	var ids []string
+	for _, match := range promptIDPattern.FindAllStringSubmatch(req.Messages[len(req.Messages)-1].Content, -1) {
+		ids = append(ids, match[1])
+	}
+	if f.keep > 0 && len(ids) > f.keep {
+		ids = ids[:f.keep]
+	}
+	choice := llm.Choice{}
+	choice.Message.Content = clusterResponse(ids)
+	return &llm.ChatCompletionResponse{Choices: []llm.Choice{choice}}, nil
+}
+
+func TestBatchTunerShrinksAndRecovers(t *testing.T) {
+	items := batchItems(40)
+
+	// a degraded model only ever places the first eight items of a prompt
+	client := &forgetfulChatClient{keep: 8}
+	tuner := NewBatchTuner(8, 32, 0.1, nil)
+	tuner.Window = 4
+	clusterer := &LLMClusterer{Client: client, Model: "test-model", MaxItems: 32, Tuner: tuner}
+
+	for i := 0; i < 10; i++ {
+		if _, err := clusterer.buildWithLLM(context.Background(), items); err != nil {
+			t.Fatalf("build: %v", err)
+		}
+	}
+	if size := tuner.Size("test-model"); size != 8 {
+		t.Fatalf("expected batch size to shrink to the minimum, got %d", size)
+	}
+	shrunk := tuner.Stats()[0]
+	if shrunk.Shrinks == 0 || shrunk.Forgotten == 0 {
+		t.Fatalf("expected shrink statistics, got %+v", shrunk)
+	}
+
+	// quality recovers: every item is placed again
+	client.keep = 0
+	for i := 0; i < 4; i++ {
+		if _, err := clusterer.buildWithLLM(context.Background(), items); err != nil {
+			t.Fatalf("build: %v", err)
+		}
+	}
+	recovered := tuner.Stats()[0]
+	if recovered.Size <= 8 || recovered.Grows == 0 {
+		t.Fatalf("expected batch size to grow back, got %+v", recovered)
+	}
+	if recovered.Size-8 != recovered.Grows {
+		t.Fatalf("expected growth by one per clean window, got %+v", recovered)
+	}
+}
+
+func TestLLMClustererKeepsEveryItemAcrossBatches(t *testing.T) {
+	items := batchItems(40)
+	tuner := NewBatchTuner(8, 40, 0.1, nil)
+	for i := 0; i < 3; i++ {
+		tuner.Observe("test-model", 40, 20)
+	}
+	size := tuner.Size("test-model")
+	if size >= len(items) {
+		t.Fatalf("expected a shrunk batch size, got %d", size)
+	}
+
+	client := &forgetfulChatClient{}
+	clusterer := &LLMClusterer{Client: client, Model: "test-model", MaxItems: 40, Tuner: tuner}
+	clusters, err := clusterer.BuildClusters(context.Background(), items)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+
+	if want := (len(items) + size - 1) / size; client.calls != want {
+		t.Fatalf("expected %d requests of %d items, got %d", want, size, client.calls)
+	}
+	seen := make(map[string]bool)
+	ids := make(map[string]bool)
+	for _, cluster := range clusters {
+		if ids[cluster.ID] {
+			t.Fatalf("duplicate cluster id %s", cluster.ID)
+		}
+		ids[cluster.ID] = true
+		for _, item := range cluster.Items {
+			seen[item.ID] = true
+		}
+	}
+	if len(seen) != len(items) {
+		t.Fatalf("expected all %d items clustered, got %d", len(items), len(seen))
+	}
+}
+
+func TestBatchTunerPersistsLearnedSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	st, err := store.Open(path)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	tuner := NewBatchTuner(5, 40, 0.1, st)
+	for i := 0; i < 3; i++ {
+		tuner.Observe("test-model", 40, 10)
+	}
+	learned := tuner.Size("test-model")
+	if learned >= 40 {
+		t.Fatalf("expected batch size to shrink, got %d", learned)
+	}
+
+	reopened, err := store.Open(path)
+	if err != nil {
+		t.Fatalf("reopen store: %v", err)
+	}
+	restarted := NewBatchTuner(5, 40, 0.1, reopened)
+	if size := restarted.Size("test-model"); size != learned {
+		t.Fatalf("expected persisted size %d, got %d", learned, size)
+	}
+	if size := restarted.Size("other-model"); size != 40 {
+		t.Fatalf("unknown models should start at the maximum, got %d", size)
+	}
+}
+
+func TestNewBatchTunerKeepsMaxAsHardCap(t *testing.T) {
+	tuner := NewBatchTuner(10, 5, 0.1, nil)
+	if size := tuner.Size("m"); size != 5 {
+		t.Fatalf("expected max items to cap the batch at 5, got %d", size)
+	}
+	if tuner.Min != 5 {
+		t.Fatalf("expected min to be lowered to max, got %d", tuner.Min)
+	}
+}
+
+func TestNewBatchTunerLeavesUncappedRequestsAlone(t *testing.T) {
+	tuner := NewBatchTuner(10, 0, 0.1, nil)
+	if tuner != nil {
+		t.Fatalf("expected no tuner without a max, got size %d", tuner.Size("m"))
+	}
+
+	fake := &forgetfulChatClient{}
+	clusterer := &LLMClusterer{Client: fake, Model: "m", Tuner: tuner}
+	clusters, err := clusterer.BuildClusters(context.Background(), batchItems(25))
+	if err != nil {
+		t.Fatalf("BuildClusters: %v", err)
+	}
+	if fake.calls != 1 || len(clusters) != 1 || len(clusters[0].Items) != 25 {
+		t.Fatalf("expected all items in one request, got %d calls and %d clusters", fake.calls, len(clusters))
+	}
+}
@@ -2,6 +2,9 @@ package radar
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
 	"sort"
 	"strings"
 	"time"
@@ -17,6 +20,13 @@ type Cluster struct {
 	StartTime   time.Time
 	EndTime     time.Time
 	Annotations *ClusterAnnotations
+	// Confidence in [0, 1] says how sure the engine is that the items belong together.
+	Confidence float64
+	// HasConfidence is set when the engine filled Confidence, so an explicit 0 is
+	// kept; otherwise scoring estimates it from the items.
+	HasConfidence bool
+	// Noise marks items the engine considered unrelated chatter rather than an event.
+	Noise bool
 }
 
 // ClusterAnnotations captures optional metadata supplied by LLMs.
@@ -97,9 +107,31 @@ func (c HeuristicClusterer) BuildClusters(_ context.Context, items []NewsItem) (
 		}
 	}
 
+	for idx := range clusters {
+		clusters[idx].Confidence = heuristicConfidence(clusters[idx].Items)
+		clusters[idx].HasConfidence = true
+	}
+
 	return clusters, nil
 }
 
+// heuristicConfidence estimates cluster cohesion when the engine provides no
+// explicit confidence: singletons are a coin flip, and every additional item
+// sharing a ticker or entity with the primary adds corroboration.
+func heuristicConfidence(items []NewsItem) float64 {
+	if len(items) <= 1 {
+		return 0.5
+	}
+	linked := 0
+	for _, item := range items[1:] {
+		if sharesToken(items[0].Tickers, item.Tickers) || sharesToken(items[0].Entities, item.Entities) {
+			linked++
+		}
+	}
+	confidence := 0.55 + 0.1*float64(len(items)-1) + 0.15*float64(linked)/float64(len(items)-1)
+	return roundTo(math.Min(0.95, confidence), 3)
+}
+
 func withinWindow(start, end, ts time.Time, window time.Duration) bool {
 	if ts.Before(start.Add(-window)) {
 		return false
@@ -189,3 +221,21 @@ func tokenize(s string) []string {
 	}
 	return tokens
 }
+
+// EventID derives an event identifier that survives re-clustering as long as
+// the event's earliest item stays the same: unlike the cluster ID or headline it
+// does not change when later news joins the cluster or another engine runs.
+func EventID(cluster Cluster) string {
+	var anchor NewsItem
+	for idx, item := range cluster.Items {
+		if idx == 0 || item.PublishedAt.Before(anchor.PublishedAt) ||
+			(item.PublishedAt.Equal(anchor.PublishedAt) && item.ID < anchor.ID) {
+			anchor = item
+		}
+	}
+	if anchor.ID == "" {
+		anchor = cluster.Primary
+	}
+	sum := sha256.Sum256([]byte(anchor.ID))
+	return "evt_" + hex.EncodeToString(sum[:8])
+}
@@ -3,9 +3,9 @@ package radar
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
-	"time"
 )
 
 type rawNewsItem struct {
@@ -25,62 +25,83 @@ type rawNewsItem struct {
 	ImportanceTag string   `json:"importance_tag"`
 }
 
-func decodeNewsItems(data []byte) ([]NewsItem, error) {
+// Rejection describes a record dropped while decoding a news dump.
+type Rejection struct {
+	Index  int    `json:"index"`
+	ID     string `json:"id,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// DecodeReport summarises a decoding pass over a news dump.
+type DecodeReport struct {
+	Records  int         `json:"records"`
+	Accepted int         `json:"accepted"`
+	Rejected []Rejection `json:"rejected,omitempty"`
+	// TimestampFormats counts accepted records whose published_at was not
+	// canonical RFC3339, by the format that matched.
+	TimestampFormats map[string]int `json:"timestamp_formats,omitempty"`
+}
+
+// decodeNewsItems decodes a JSON array of news records. Records that cannot be
+// used are skipped and listed in the report; with strict set the first record
+// with an unparseable timestamp fails the whole decode instead, while records
+// missing a headline or url are still skipped. Timestamps are parsed with tsOpts.
+func decodeNewsItems(data []byte, strict bool, tsOpts TimestampOptions) ([]NewsItem, DecodeReport, error) {
 	decoder := json.NewDecoder(bytes.NewReader(data))
 	decoder.DisallowUnknownFields()
 
 	var raws []rawNewsItem
 	if err := decoder.Decode(&raws); err != nil {
-		return nil, fmt.Errorf("decode JSON: %w", err)
+		return nil, DecodeReport{}, fmt.Errorf("decode JSON: %w", err)
 	}
 
+	report := DecodeReport{Records: len(raws)}
 	items := make([]NewsItem, 0, len(raws))
-	for _, r := range raws {
-		if r.Headline == "" || r.URL == "" {
+	for idx, r := range raws {
+		item, format, err := decodeNewsItem(r, tsOpts)
+		if err != nil {
+			if strict && !errors.Is(err, ErrInvalidItem) {
+				return nil, report, fmt.Errorf("record %d (id %q): %w", idx, r.ID, err)
+			}
+			report.Rejected = append(report.Rejected, Rejection{Index: idx, ID: r.ID, Reason: err.Error()})
 			continue
 		}
-		published, err := time.Parse(time.RFC3339, r.PublishedAt)
-		if err != nil {
-			return nil, fmt.Errorf("parse time for %s: %w", r.ID, err)
+		if format != "" {
+			if report.TimestampFormats == nil {
+				report.TimestampFormats = make(map[string]int)
+			}
+			report.TimestampFormats[format]++
 		}
-		items = append(items, NewsItem{
-			ID:            r.ID,
-			Headline:      r.Headline,
-			Summary:       r.Summary,
-			Body:          r.Body,
-			Source:        r.Source,
-			URL:           r.URL,
-			Language:      r.Language,
-			PublishedAt:   published,
-			Tickers:       dedupeStrings(r.Tickers),
-			Entities:      dedupeStrings(r.Entities),
-			Country:       r.Country,
-			Category:      r.Category,
-			Sentiment:     r.Sentiment,
-			ImportanceTag: r.ImportanceTag,
-		})
+		items = append(items, item)
 	}
+	report.Accepted = len(items)
 
-	return items, nil
+	return items, report, nil
 }
 
-func dedupeStrings(values []string) []string {
-	if len(values) <= 1 {
-		return values
+func decodeNewsItem(r rawNewsItem, tsOpts TimestampOptions) (NewsItem, string, error) {
+	if strings.TrimSpace(r.Headline) == "" || strings.TrimSpace(r.URL) == "" {
+		return NewsItem{}, "", fmt.Errorf("%w: headline and url are required", ErrInvalidItem)
 	}
-	seen := make(map[string]struct{}, len(values))
-	var out []string
-	for _, v := range values {
-		v = strings.TrimSpace(v)
-		if v == "" {
-			continue
-		}
-		key := strings.ToUpper(v)
-		if _, ok := seen[key]; ok {
-			continue
-		}
-		seen[key] = struct{}{}
-		out = append(out, v)
+	published, format, err := ParseTimestampWith(r.PublishedAt, tsOpts)
+	if err != nil {
+		return NewsItem{}, "", fmt.Errorf("parse time: %w", err)
 	}
-	return out
+	item, _, err := Normalize(NewsItem{
+		ID:            r.ID,
+		Headline:      r.Headline,
+		Summary:       r.Summary,
+		Body:          r.Body,
+		Source:        r.Source,
+		URL:           r.URL,
+		Language:      r.Language,
+		PublishedAt:   published,
+		Tickers:       r.Tickers,
+		Entities:      r.Entities,
+		Country:       r.Country,
+		Category:      r.Category,
+		Sentiment:     r.Sentiment,
+		ImportanceTag: r.ImportanceTag,
+	}, NormalizeOptions{})
+	return item, format, err
 }
@@ -0,0 +1,98 @@
+package radar
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func partialDataPath() string {
+	return filepath.Join("testdata", "partial_news.json")
+}
+
+func TestDecodeNewsItemsReportsRejections(t *testing.T) {
+	raw, err := os.ReadFile(partialDataPath())
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	items, report, err := decodeNewsItems(raw, false, TimestampOptions{})
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(items) != 5 || report.Records != 8 || report.Accepted != 5 {
+		t.Fatalf("expected 5 of 8 records accepted, got %d items and report %+v", len(items), report)
+	}
+
+	want := []Rejection{{Index: 1, ID: "bad-time"}, {Index: 4, ID: "bad-url"}, {Index: 6, ID: "bad-empty-time"}}
+	if len(report.Rejected) != len(want) {
+		t.Fatalf("expected %d rejections, got %+v", len(want), report.Rejected)
+	}
+	for idx, rejection := range report.Rejected {
+		if rejection.Index != want[idx].Index || rejection.ID != want[idx].ID || rejection.Reason == "" {
+			t.Errorf("rejection %d: got %+v, want index %d id %s with a reason", idx, rejection, want[idx].Index, want[idx].ID)
+		}
+	}
+
+	_, _, err = decodeNewsItems(raw, true, TimestampOptions{})
+	if err == nil || !strings.Contains(err.Error(), "bad-time") {
+		t.Fatalf("strict decode should fail on the first bad timestamp, got %v", err)
+	}
+}
+
+func TestDecodeNewsItemsStrictSkipsIncompleteRecords(t *testing.T) {
+	raw := []byte(`[
+		{"id": "good", "headline": "Headline", "url": "https://example.com/a", "published_at": "2025-10-03T08:00:00Z"},
+		{"id": "no-url", "headline": "Missing link", "published_at": "2025-10-03T09:00:00Z"},
+		{"id": "no-headline", "url": "https://example.com/b", "published_at": "2025-10-03T09:00:00Z"}
+	]`)
+
+	items, report, err := decodeNewsItems(raw, true, TimestampOptions{})
+	if err != nil {
+		t.Fatalf("strict decode should skip records without headline or url, got %v", err)
+	}
+	if len(items) != 1 || items[0].ID != "good" || len(report.Rejected) != 2 {
+		t.Fatalf("expected only the complete record, got %d items and report %+v", len(items), report)
+	}
+}
+
+func TestStaticFileSourceSkipsBadRecords(t *testing.T) {
+	source, err := NewStaticFileSource("partial", partialDataPath())
+	if err != nil {
+		t.Fatalf("source: %v", err)
+	}
+	sources, err := NewSourceRegistry(source)
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+	pipeline, err := NewPipeline(sources, DefaultClusterer(), DefaultScorer())
+	if err != nil {
+		t.Fatalf("pipeline: %v", err)
+	}
+
+	from := time.Date(2025, 10, 3, 0, 0, 0, 0, time.UTC)
+	events, err := pipeline.Run(context.Background(), QueryParams{From: from, To: from.Add(24 * time.Hour), Limit: 10})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	var served int
+	for _, event := range events {
+		served += len(event.Sources)
+	}
+	if served != 5 {
+		t.Fatalf("expected all 5 good items to reach the pipeline, got %d", served)
+	}
+
+	stats := source.Stats()
+	if stats.Loads != 1 || stats.Records != 8 || stats.Accepted != 5 || stats.Rejected != 3 || len(stats.Rejections) != 3 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	source.Strict = true
+	if _, err := source.Fetch(context.Background(), from, from.Add(24*time.Hour)); err == nil {
+		t.Fatalf("strict source should fail on bad records")
+	}
+}
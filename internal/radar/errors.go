@@ -0,0 +1,49 @@
+package radar
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNoSources is returned when a registry or pipeline has no sources to fetch from.
+	ErrNoSources = errors.New("radar: no sources registered")
+	// ErrWindowEmpty is returned by Pipeline.Run when no items fall into the requested window.
+	ErrWindowEmpty = errors.New("radar: no news items in window")
+	// ErrCanceled is returned when the caller's context ends before the run completes.
+	ErrCanceled = errors.New("radar: run canceled")
+)
+
+// SourceError reports a failure of a single upstream source.
+type SourceError struct {
+	Name string
+	Err  error
+}
+
+func (e *SourceError) Error() string {
+	return fmt.Sprintf("radar: fetch from %s: %v", e.Name, e.Err)
+}
+
+func (e *SourceError) Unwrap() error { return e.Err }
+
+// ClusterError reports a failure of a clustering engine. Fallback is set when the
+// error was produced by the fallback engine after the primary one had already failed.
+type ClusterError struct {
+	Engine   string
+	Fallback bool
+	Err      error
+}
+
+func (e *ClusterError) Error() string {
+	if e.Fallback {
+		return fmt.Sprintf("radar: %s clusterer fallback: %v", e.Engine, e.Err)
+	}
+	return fmt.Sprintf("radar: %s clusterer: %v", e.Engine, e.Err)
+}
+
+func (e *ClusterError) Unwrap() error { return e.Err }
+
+// canceled wraps cause with ErrCanceled so both remain visible to errors.Is.
+func canceled(cause error) error {
+	return fmt.Errorf("%w: %w", ErrCanceled, cause)
+}
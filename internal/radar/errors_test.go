@@ -0,0 +1,108 @@
+package radar
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type failingSource struct {
+	name string
+	err  error
+}
+
+func (f failingSource) Name() string { return f.name }
+
+func (f failingSource) Fetch(ctx context.Context, from, to time.Time) ([]NewsItem, error) {
+	return nil, f.err
+}
+
+func TestFetchAllReturnsSourceError(t *testing.T) {
+	cause := errors.New("upstream down")
+	sources, err := NewSourceRegistry(NewIngestSource("ingest"), failingSource{name: "wire", err: cause})
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+
+	_, err = sources.FetchAll(context.Background(), time.Time{}, time.Now())
+	if err == nil {
+		t.Fatalf("expected error from failing source")
+	}
+
+	var sourceErr *SourceError
+	if !errors.As(err, &sourceErr) {
+		t.Fatalf("expected *SourceError, got %T: %v", err, err)
+	}
+	if sourceErr.Name != "wire" {
+		t.Errorf("unexpected source name: %s", sourceErr.Name)
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("source error should wrap the underlying cause")
+	}
+}
+
+func TestNewSourceRegistryRequiresSources(t *testing.T) {
+	if _, err := NewSourceRegistry(); !errors.Is(err, ErrNoSources) {
+		t.Fatalf("expected ErrNoSources, got %v", err)
+	}
+}
+
+func TestPipelineRunReportsEmptyWindow(t *testing.T) {
+	sources, err := NewSourceRegistry(NewIngestSource("ingest"))
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+	pipeline, err := NewPipeline(sources, DefaultClusterer(), DefaultScorer())
+	if err != nil {
+		t.Fatalf("pipeline: %v", err)
+	}
+
+	_, err = pipeline.Run(context.Background(), QueryParams{From: time.Now().Add(-time.Hour), To: time.Now()})
+	if !errors.Is(err, ErrWindowEmpty) {
+		t.Fatalf("expected ErrWindowEmpty, got %v", err)
+	}
+}
+
+func TestPipelineRunReportsCancellation(t *testing.T) {
+	ingest := NewIngestSource("ingest")
+	ingest.Add(NewsItem{ID: "n1", Headline: "One", URL: "https://example.com/1"})
+	sources, err := NewSourceRegistry(ingest)
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+	pipeline, err := NewPipeline(sources, DefaultClusterer(), DefaultScorer())
+	if err != nil {
+		t.Fatalf("pipeline: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = pipeline.Run(ctx, QueryParams{From: time.Now().Add(-time.Hour), To: time.Now().Add(time.Hour)})
+	if !errors.Is(err, ErrCanceled) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected ErrCanceled wrapping context.Canceled, got %v", err)
+	}
+
+	var sourceErr *SourceError
+	if !errors.As(err, &sourceErr) || sourceErr.Name != "ingest" {
+		t.Fatalf("expected cancellation to keep the source error, got %v", err)
+	}
+}
+
+func TestLLMClustererWithoutFallbackReturnsClusterError(t *testing.T) {
+	clusterer := &LLMClusterer{
+		Client: &fakeChatClient{err: errors.New("boom")},
+		Model:  "gemini-2.5-flash",
+	}
+
+	_, err := clusterer.BuildClusters(context.Background(), []NewsItem{{ID: "n1", Headline: "One"}})
+
+	var clusterErr *ClusterError
+	if !errors.As(err, &clusterErr) {
+		t.Fatalf("expected *ClusterError, got %T: %v", err, err)
+	}
+	if clusterErr.Engine != "llm" || clusterErr.Fallback {
+		t.Errorf("unexpected cluster error: %+v", clusterErr)
+	}
+}
@@ -0,0 +1,98 @@
+package radar
+
+import (
+	"sort"
+	"time"
+)
+
+// Heatmap is a ticker × time matrix of event hotness.
+type Heatmap struct {
+	// Buckets holds len(Values[i])+1 boundaries; bucket j spans [Buckets[j], Buckets[j+1]).
+	Buckets []time.Time `json:"buckets"`
+	Tickers []string    `json:"tickers"`
+	// Values[i][j] is the hottest event touching Tickers[i] within bucket j, or 0.
+	Values [][]float64 `json:"values"`
+}
+
+// HeatmapBounds aligns a trailing window ending at to onto whole-hour bucket
+// boundaries and returns the window start and the number of buckets.
+func HeatmapBounds(to time.Time, window, bucket time.Duration) (time.Time, int) {
+	end := to.UTC().Truncate(time.Hour)
+	if end.Before(to) {
+		end = end.Add(time.Hour)
+	}
+	count := int((window + bucket - 1) / bucket)
+	if count < 1 {
+		count = 1
+	}
+	return end.Add(-time.Duration(count) * bucket), count
+}
+
+// BuildHeatmap spreads each event's hotness over the buckets its sources were
+// published in, keeping the maximum per ticker and bucket, and returns the top
+// tickers ranked by their total across buckets.
+func BuildHeatmap(events []Event, start time.Time, bucket time.Duration, count, top int) Heatmap {
+	heatmap := Heatmap{
+		Buckets: make([]time.Time, count+1),
+		Tickers: []string{},
+		Values:  [][]float64{},
+	}
+	for idx := range heatmap.Buckets {
+		heatmap.Buckets[idx] = start.Add(time.Duration(idx) * bucket)
+	}
+	end := heatmap.Buckets[count]
+
+	rows := make(map[string][]float64)
+	for _, event := range events {
+		touched := make(map[int]struct{})
+		for _, src := range event.Sources {
+			if src.Published.Before(start) || !src.Published.Before(end) {
+				continue
+			}
+			touched[int(src.Published.Sub(start)/bucket)] = struct{}{}
+		}
+		if len(touched) == 0 {
+			continue
+		}
+		for _, ticker := range event.Tickers {
+			row, ok := rows[ticker]
+			if !ok {
+				row = make([]float64, count)
+				rows[ticker] = row
+			}
+			for idx := range touched {
+				if event.Hotness > row[idx] {
+					row[idx] = event.Hotness
+				}
+			}
+		}
+	}
+
+	type ranked struct {
+		ticker string
+		total  float64
+	}
+	totals := make([]ranked, 0, len(rows))
+	for ticker, row := range rows {
+		var total float64
+		for _, v := range row {
+			total += v
+		}
+		totals = append(totals, ranked{ticker: ticker, total: total})
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].total != totals[j].total {
+			return totals[i].total > totals[j].total
+		}
+		return totals[i].ticker < totals[j].ticker
+	})
+	if top > 0 && len(totals) > top {
+		totals = totals[:top]
+	}
+
+	for _, entry := range totals {
+		heatmap.Tickers = append(heatmap.Tickers, entry.ticker)
+		heatmap.Values = append(heatmap.Values, rows[entry.ticker])
+	}
+	return heatmap
+}
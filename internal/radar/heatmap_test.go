@@ -0,0 +1,72 @@
+package radar
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHeatmapBoundsAlignToHours(t *testing.T) {
+	to := time.Date(2025, 10, 3, 14, 25, 0, 0, time.UTC)
+
+	start, count := HeatmapBounds(to, 24*time.Hour, time.Hour)
+	if want := time.Date(2025, 10, 2, 15, 0, 0, 0, time.UTC); !start.Equal(want) || count != 24 {
+		t.Fatalf("got start %s count %d, want %s and 24", start, count, want)
+	}
+
+	start, count = HeatmapBounds(to, 5*time.Hour, 2*time.Hour)
+	if want := time.Date(2025, 10, 3, 9, 0, 0, 0, time.UTC); !start.Equal(want) || count != 3 {
+		t.Fatalf("partial bucket should widen the window: got start %s count %d", start, count)
+	}
+
+	aligned := time.Date(2025, 10, 3, 14, 0, 0, 0, time.UTC)
+	if start, _ := HeatmapBounds(aligned, time.Hour, time.Hour); !start.Equal(aligned.Add(-time.Hour)) {
+		t.Fatalf("aligned end should not be pushed forward, got %s", start)
+	}
+}
+
+func TestBuildHeatmap(t *testing.T) {
+	start := time.Date(2025, 10, 3, 0, 0, 0, 0, time.UTC)
+	at := func(h, m int) SourceRef {
+		return SourceRef{Published: start.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute)}
+	}
+
+	events := []Event{
+		{Hotness: 0.8, Tickers: []string{"NTCH", "^NDX"}, Sources: []SourceRef{at(0, 10), at(2, 30)}},
+		{Hotness: 0.5, Tickers: []string{"NTCH"}, Sources: []SourceRef{at(2, 45), at(3, 5)}},
+		{Hotness: 0.6, Tickers: []string{"SBER"}, Sources: []SourceRef{at(1, 0)}},
+		{Hotness: 0.4, Tickers: []string{"GAZP"}, Sources: []SourceRef{at(3, 59)}},
+		{Hotness: 0.9, Tickers: []string{"OUT"}, Sources: []SourceRef{at(4, 0), at(-1, 0)}},
+	}
+
+	heatmap := BuildHeatmap(events, start, time.Hour, 4, 3)
+
+	if len(heatmap.Buckets) != 5 || !heatmap.Buckets[0].Equal(start) || !heatmap.Buckets[4].Equal(start.Add(4*time.Hour)) {
+		t.Fatalf("unexpected bucket boundaries: %v", heatmap.Buckets)
+	}
+
+	wantTickers := []string{"NTCH", "^NDX", "SBER"}
+	if !reflect.DeepEqual(heatmap.Tickers, wantTickers) {
+		t.Fatalf("top tickers: got %v, want %v", heatmap.Tickers, wantTickers)
+	}
+
+	wantValues := [][]float64{
+		{0.8, 0, 0.8, 0.5},
+		{0.8, 0, 0.8, 0},
+		{0, 0.6, 0, 0},
+	}
+	if !reflect.DeepEqual(heatmap.Values, wantValues) {
+		t.Fatalf("values: got %v, want %v", heatmap.Values, wantValues)
+	}
+}
+
+func TestBuildHeatmapEmpty(t *testing.T) {
+	start := time.Date(2025, 10, 3, 0, 0, 0, 0, time.UTC)
+	heatmap := BuildHeatmap(nil, start, time.Hour, 2, 5)
+	if heatmap.Tickers == nil || heatmap.Values == nil || len(heatmap.Tickers) != 0 {
+		t.Fatalf("empty heatmap should have empty, non-nil rows: %+v", heatmap)
+	}
+	if len(heatmap.Buckets) != 3 {
+		t.Fatalf("expected 3 boundaries, got %d", len(heatmap.Buckets))
+	}
+}
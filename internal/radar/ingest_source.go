@@ -2,6 +2,7 @@ package radar
 
 import (
 	"context"
+	"reflect"
 	"sort"
 	"sync"
 	"time"
@@ -9,6 +10,10 @@ import (
 	"github.com/google/uuid"
 )
 
+// maxRevisions bounds the superseded versions kept per item; once exceeded the
+// oldest are dropped, so as_of runs before them see the oldest retained version.
+const maxRevisions = 16
+
 // IngestSource stores ad-hoc news items submitted via the API.
 type IngestSource struct {
 	name  string
@@ -28,6 +33,10 @@ func NewIngestSource(name string) *IngestSource {
 func (s *IngestSource) Name() string { return s.name }
 
 // Add registers a news item in the ingest source, generating defaults when missing.
+// IngestedAt defaults to the time of the call. A correction of a known ID keeps the
+// first IngestedAt, records its own arrival in RevisedAt and retains the previous
+// versions in Revisions so as_of runs can replay what was current at the time.
+// Resubmitting identical content is a no-op that returns the stored item.
 func (s *IngestSource) Add(item NewsItem) NewsItem {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -35,23 +44,41 @@ func (s *IngestSource) Add(item NewsItem) NewsItem {
 	if item.ID == "" {
 		item.ID = uuid.NewString()
 	}
+	now := time.Now().UTC()
 	if item.PublishedAt.IsZero() {
-		item.PublishedAt = time.Now().UTC()
+		item.PublishedAt = now
+	}
+	if item.IngestedAt.IsZero() {
+		item.IngestedAt = now
 	}
+	item = cloneNewsItem(item)
 
-	// Replace existing record with same ID if found.
+	// Replace existing record with same ID if found, keeping its history.
 	for idx := range s.items {
 		if s.items[idx].ID == item.ID {
+			prev := s.items[idx]
+			if sameContent(prev, item) {
+				return cloneNewsItem(prev)
+			}
+			item.RevisedAt = item.IngestedAt
+			item.IngestedAt = prev.IngestedAt
+			item.Revisions = append(prev.Revisions, prev)
+			item.Revisions[len(item.Revisions)-1].Revisions = nil
+			if extra := len(item.Revisions) - maxRevisions; extra > 0 {
+				item.Revisions = append([]NewsItem(nil), item.Revisions[extra:]...)
+			}
 			s.items[idx] = item
-			return s.items[idx]
+			return cloneNewsItem(item)
 		}
 	}
 
 	s.items = append(s.items, item)
-	return item
+	return cloneNewsItem(item)
 }
 
-// Fetch returns items within the requested timeframe.
+// Fetch returns copies of the items within the requested timeframe. An item is
+// included when any of its versions was published in the window, since an as_of
+// run may select an earlier version; the pipeline re-checks the chosen one.
 func (s *IngestSource) Fetch(ctx context.Context, from, to time.Time) ([]NewsItem, error) {
 	select {
 	case <-ctx.Done():
@@ -64,10 +91,10 @@ func (s *IngestSource) Fetch(ctx context.Context, from, to time.Time) ([]NewsIte
 
 	out := make([]NewsItem, 0, len(s.items))
 	for _, item := range s.items {
-		if item.PublishedAt.Before(from) || item.PublishedAt.After(to) {
+		if !anyVersionWithin(item, from, to) {
 			continue
 		}
-		out = append(out, item)
+		out = append(out, cloneNewsItem(item))
 	}
 
 	sort.Slice(out, func(i, j int) bool {
@@ -98,3 +125,29 @@ func (s *IngestSource) PruneOlderThan(ts time.Time) int {
 	s.items = filtered
 	return removed
 }
+
+// sameContent reports whether two versions of an item differ only in their
+// ingest bookkeeping.
+func sameContent(a, b NewsItem) bool {
+	for _, item := range []*NewsItem{&a, &b} {
+		item.PublishedAt = item.PublishedAt.UTC()
+		item.IngestedAt, item.RevisedAt, item.Revisions = time.Time{}, time.Time{}, nil
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func anyVersionWithin(item NewsItem, from, to time.Time) bool {
+	if inWindow(item.PublishedAt, from, to) {
+		return true
+	}
+	for _, rev := range item.Revisions {
+		if inWindow(rev.PublishedAt, from, to) {
+			return true
+		}
+	}
+	return false
+}
+
+func inWindow(ts, from, to time.Time) bool {
+	return !ts.Before(from) && !ts.After(to)
+}
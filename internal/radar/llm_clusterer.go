@@ -20,10 +20,16 @@ type LLMClusterer struct {
 	Client      llm.ChatClient
 	Model       string
 	Temperature float64
+	TopP        float64
 	MaxTokens   int
-	MaxItems    int
-	Fallback    ClusterEngine
-	CacheTTL    time.Duration
+	// MaxItems caps how many items go into one request; larger windows are
+	// split into several requests.
+	MaxItems int
+	Fallback ClusterEngine
+	CacheTTL time.Duration
+	// Tuner, when set, replaces MaxItems with a per-model size adapted to how
+	// many items the model forgets to place.
+	Tuner *BatchTuner
 
 	cacheMu        sync.RWMutex
 	cacheKey       string
@@ -37,7 +43,7 @@ func (c *LLMClusterer) BuildClusters(ctx context.Context, items []NewsItem) ([]C
 		return nil, nil
 	}
 
-	signature := signatureForItems(items, c.MaxItems)
+	signature := signatureForItems(items)
 	if clusters, ok := c.loadFromCache(signature); ok {
 		log.Printf("LLMClusterer: cache hit for %d items", len(items))
 		return clusters, nil
@@ -56,15 +62,20 @@ func (c *LLMClusterer) BuildClusters(ctx context.Context, items []NewsItem) ([]C
 	return clusters, nil
 }
 
-func (c *LLMClusterer) buildWithLLM(ctx context.Context, items []NewsItem) ([]Cluster, error) {
-	limited := items
-	if c.MaxItems > 0 && len(items) > c.MaxItems {
-		limited = make([]NewsItem, c.MaxItems)
-		copy(limited, items[:c.MaxItems])
+// batchSize returns how many items to send in one request; zero means all.
+func (c *LLMClusterer) batchSize() int {
+	if c.Tuner != nil {
+		return c.Tuner.Size(c.Model)
 	}
+	return c.MaxItems
+}
 
-	sorted := make([]NewsItem, len(limited))
-	copy(sorted, limited)
+// buildWithLLM sends the items in time-ordered batches of batchSize and merges
+// the clusters. Events spanning two batches come back as separate clusters,
+// which is preferable to dropping the items that do not fit one prompt.
+func (c *LLMClusterer) buildWithLLM(ctx context.Context, items []NewsItem) ([]Cluster, error) {
+	sorted := make([]NewsItem, len(items))
+	copy(sorted, items)
 	sort.Slice(sorted, func(i, j int) bool {
 		if sorted[i].PublishedAt.Equal(sorted[j].PublishedAt) {
 			return sorted[i].ID < sorted[j].ID
@@ -72,7 +83,43 @@ func (c *LLMClusterer) buildWithLLM(ctx context.Context, items []NewsItem) ([]Cl
 		return sorted[i].PublishedAt.Before(sorted[j].PublishedAt)
 	})
 
-	payload, err := c.buildPrompt(sorted)
+	size := c.batchSize()
+	if size <= 0 || size > len(sorted) {
+		size = len(sorted)
+	}
+	batches := (len(sorted) + size - 1) / size
+
+	var clusters []Cluster
+	for batch := 0; batch < batches; batch++ {
+		end := (batch + 1) * size
+		if end > len(sorted) {
+			end = len(sorted)
+		}
+		chunk := sorted[batch*size : end]
+
+		chunkClusters, err := c.requestClusters(ctx, chunk)
+		if err != nil {
+			return nil, err
+		}
+		if batches > 1 {
+			// engine IDs such as "event_1" repeat across batches
+			for idx := range chunkClusters {
+				chunkClusters[idx].ID = fmt.Sprintf("b%d_%s", batch+1, chunkClusters[idx].ID)
+			}
+		}
+		clusters = append(clusters, chunkClusters...)
+	}
+
+	if len(clusters) == 0 {
+		return nil, fmt.Errorf("llm response returned no clusters")
+	}
+
+	return clusters, nil
+}
+
+// requestClusters clusters one batch with a single completion request.
+func (c *LLMClusterer) requestClusters(ctx context.Context, chunk []NewsItem) ([]Cluster, error) {
+	payload, err := c.buildPrompt(chunk)
 	if err != nil {
 		return nil, err
 	}
@@ -82,10 +129,10 @@ func (c *LLMClusterer) buildWithLLM(ctx context.Context, items []NewsItem) ([]Cl
 		Messages:    payload,
 		Temperature: c.Temperature,
 		MaxTokens:   c.MaxTokens,
-		TopP:        0.9,
+		TopP:        c.TopP,
 	}
 
-	log.Printf("LLMClusterer: requesting clustering for %d items via %s", len(sorted), c.Model)
+	log.Printf("LLMClusterer: requesting clustering for %d items via %s", len(chunk), c.Model)
 
 	resp, err := c.Client.ChatCompletion(ctx, req)
 	if err != nil {
@@ -95,26 +142,24 @@ func (c *LLMClusterer) buildWithLLM(ctx context.Context, items []NewsItem) ([]Cl
 		return nil, fmt.Errorf("llm response missing choices")
 	}
 
-	clusters, err := c.parseResponse(resp.Choices[0].Message.Content, items)
+	clusters, err := c.parseResponse(resp.Choices[0].Message.Content, chunk)
 	if err != nil {
 		return nil, err
 	}
-
-	if len(clusters) == 0 {
-		return nil, fmt.Errorf("llm response returned no clusters")
+	if c.Tuner != nil {
+		c.Tuner.Observe(c.Model, len(chunk), countForgotten(chunk, clusters))
 	}
-
 	return clusters, nil
 }
 
 func (c *LLMClusterer) buildWithFallback(ctx context.Context, items []NewsItem, signature string, cause error) ([]Cluster, error) {
 	log.Printf("LLMClusterer fallback: %v", cause)
 	if c.Fallback == nil {
-		return nil, cause
+		return nil, &ClusterError{Engine: "llm", Err: cause}
 	}
 	clusters, fbErr := c.Fallback.BuildClusters(ctx, items)
 	if fbErr != nil {
-		return nil, fmt.Errorf("llm fallback error: %v (original: %w)", fbErr, cause)
+		return nil, &ClusterError{Engine: "llm", Fallback: true, Err: fmt.Errorf("%w (original: %w)", fbErr, cause)}
 	}
 	c.storeInCache(signature, clusters)
 	return clusters, nil
@@ -168,6 +213,8 @@ Rules:
 - Provide both English and Russian short summaries for each cluster.
 - Provide a short justification (English + Russian) why the event matters now.
 - Infer entities and tickers from the statements when missing.
+- Rate each cluster with a "confidence" between 0 and 1 that its news truly describe one event.
+- Do not invent events for unrelated chatter: list such news ids in "noise_ids" instead of a cluster.
 
 Respond with JSON using this schema:
 {
@@ -181,9 +228,11 @@ Respond with JSON using this schema:
       "why_now_en": "...",
       "why_now_ru": "...",
       "entities": ["..."],
-      "tickers": ["..."]
+      "tickers": ["..."],
+      "confidence": 0.9
     }
-  ]
+  ],
+  "noise_ids": ["id_c"]
 }
 
 News payload:
@@ -212,14 +261,16 @@ func (c *LLMClusterer) parseResponse(content string, items []NewsItem) ([]Cluste
 			WhyNowRU      string   `json:"why_now_ru"`
 			Entities      []string `json:"entities"`
 			Tickers       []string `json:"tickers"`
+			Confidence    *float64 `json:"confidence"`
 		} `json:"clusters"`
+		NoiseIDs []string `json:"noise_ids"`
 	}
 
 	if err := json.Unmarshal([]byte(jsonPayload), &decoded); err != nil {
 		return nil, fmt.Errorf("llm response decode: %w", err)
 	}
 
-	if len(decoded.Clusters) == 0 {
+	if len(decoded.Clusters) == 0 && len(decoded.NoiseIDs) == 0 {
 		return nil, fmt.Errorf("llm response contains no clusters")
 	}
 
@@ -228,12 +279,14 @@ func (c *LLMClusterer) parseResponse(content string, items []NewsItem) ([]Cluste
 		itemByID[item.ID] = item
 	}
 
-	clusters := make([]Cluster, 0, len(decoded.Clusters))
+	clusters := make([]Cluster, 0, len(decoded.Clusters)+len(decoded.NoiseIDs))
+	assigned := make(map[string]struct{}, len(items))
 	for _, cluster := range decoded.Clusters {
 		var clusterItems []NewsItem
 		for _, id := range cluster.NewsIDs {
 			if item, ok := itemByID[id]; ok {
 				clusterItems = append(clusterItems, item)
+				assigned[id] = struct{}{}
 			}
 		}
 		if len(clusterItems) == 0 {
@@ -277,19 +330,67 @@ func (c *LLMClusterer) parseResponse(content string, items []NewsItem) ([]Cluste
 			primary.Summary = annotation.SummaryEN
 		}
 
+		confidence := heuristicConfidence(clusterItems)
+		if cluster.Confidence != nil {
+			confidence = clamp01(*cluster.Confidence)
+		}
+
 		clusters = append(clusters, Cluster{
-			ID:          preferID(cluster.ID, primary.ID),
-			Items:       clusterItems,
-			Primary:     primary,
-			StartTime:   start,
-			EndTime:     end,
-			Annotations: annotation,
+			ID:            preferID(cluster.ID, primary.ID),
+			Items:         clusterItems,
+			Primary:       primary,
+			StartTime:     start,
+			EndTime:       end,
+			Annotations:   annotation,
+			Confidence:    confidence,
+			HasConfidence: true,
+		})
+	}
+
+	// Noise items become flagged singletons so they are still scored (and capped)
+	// rather than silently dropped or merged into invented events.
+	for _, id := range decoded.NoiseIDs {
+		item, ok := itemByID[id]
+		if !ok {
+			continue
+		}
+		if _, ok := assigned[id]; ok {
+			continue
+		}
+		assigned[id] = struct{}{}
+		clusters = append(clusters, Cluster{
+			ID:            "noise_" + id,
+			Items:         []NewsItem{item},
+			Primary:       item,
+			StartTime:     item.PublishedAt,
+			EndTime:       item.PublishedAt,
+			Confidence:    0.2,
+			HasConfidence: true,
+			Noise:         true,
 		})
 	}
 
 	return clusters, nil
 }
 
+// countForgotten returns how many sent items the response left out of every
+// cluster, noise included.
+func countForgotten(sent []NewsItem, clusters []Cluster) int {
+	placed := make(map[string]struct{}, len(sent))
+	for _, cluster := range clusters {
+		for _, item := range cluster.Items {
+			placed[item.ID] = struct{}{}
+		}
+	}
+	forgotten := 0
+	for _, item := range sent {
+		if _, ok := placed[item.ID]; !ok {
+			forgotten++
+		}
+	}
+	return forgotten
+}
+
 func (c *LLMClusterer) loadFromCache(key string) ([]Cluster, bool) {
 	if key == "" {
 		return nil, false
@@ -318,7 +419,7 @@ func (c *LLMClusterer) storeInCache(key string, clusters []Cluster) {
 	c.cacheMu.Unlock()
 }
 
-func signatureForItems(items []NewsItem, maxItems int) string {
+func signatureForItems(items []NewsItem) string {
 	if len(items) == 0 {
 		return ""
 	}
@@ -332,10 +433,6 @@ func signatureForItems(items []NewsItem, maxItems int) string {
 		return sorted[i].PublishedAt.Before(sorted[j].PublishedAt)
 	})
 
-	if maxItems > 0 && len(sorted) > maxItems {
-		sorted = sorted[:maxItems]
-	}
-
 	hasher := sha256.New()
 	for _, item := range sorted {
 		hasher.Write([]byte(item.ID))
@@ -145,3 +145,103 @@ func TestLLMClustererCachesBySignature(t *testing.T) {
 		t.Fatalf("expected LLM to be called once, got %d", fake.calls)
 	}
 }
+
+func TestLLMClustererHandlesNoiseAndConfidence(t *testing.T) {
+	base := time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)
+	items := []NewsItem{
+		{ID: "n1", Headline: "Company A cuts guidance", Source: "Reuters", URL: "https://example.com/a", PublishedAt: base, Tickers: []string{"CMA"}, ImportanceTag: "guidance_cut", Sentiment: -0.8},
+		{ID: "n2", Headline: "Company A supplier halts output", Source: "Bloomberg", URL: "https://example.com/b", PublishedAt: base.Add(30 * time.Minute), Tickers: []string{"CMA"}, ImportanceTag: "supply_chain", Sentiment: -0.6},
+		{ID: "n3", Headline: "Traders chat about lunch", Source: "Bloomberg", URL: "https://example.com/c", PublishedAt: base.Add(time.Hour), ImportanceTag: "guidance_cut", Sentiment: -0.9},
+	}
+
+	fake := &fakeChatClient{response: `{
+		"clusters": [
+			{"id": "event_a", "news_ids": ["n1", "n2"], "primary_news_id": "n1", "confidence": 0.4}
+		],
+		"noise_ids": ["n3", "n1", "unknown"]
+	}`}
+	clusterer := &LLMClusterer{Client: fake, Model: "gemini-2.5-flash"}
+
+	clusters, err := clusterer.BuildClusters(context.Background(), items)
+	if err != nil {
+		t.Fatalf("BuildClusters: %v", err)
+	}
+	if len(clusters) != 2 {
+		t.Fatalf("expected event cluster plus one noise singleton, got %d", len(clusters))
+	}
+	if clusters[0].Confidence != 0.4 || clusters[0].Noise {
+		t.Errorf("unexpected event cluster: confidence %v noise %v", clusters[0].Confidence, clusters[0].Noise)
+	}
+	noise := clusters[1]
+	if !noise.Noise || len(noise.Items) != 1 || noise.Items[0].ID != "n3" {
+		t.Fatalf("expected n3 as a noise singleton, got %+v", noise)
+	}
+
+	scorer := DefaultScorer()
+	scorer.NoiseCap = 0.2
+	events := scorer.ScoreClusters(clusters)
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if !events[1].Noise || events[1].Hotness != 0.2 {
+		t.Errorf("noise event should be capped at 0.2, got %+v", events[1])
+	}
+	// 0.7 * cluster confidence + 0.3 * mean source credibility (0.88 and 0.9).
+	if events[0].Confidence != 0.547 {
+		t.Errorf("expected event confidence 0.547, got %v", events[0].Confidence)
+	}
+
+	scorer.ExcludeNoise = true
+	if events := scorer.ScoreClusters(clusters); len(events) != 1 || events[0].Noise {
+		t.Errorf("noise clusters should be excluded, got %+v", events)
+	}
+}
+
+func TestLLMClustererKeepsExplicitZeroConfidence(t *testing.T) {
+	base := time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)
+	items := []NewsItem{
+		{ID: "n1", Headline: "Company A cuts guidance", Source: "Reuters", URL: "https://example.com/a", PublishedAt: base, Tickers: []string{"CMA"}},
+		{ID: "n2", Headline: "Company A cuts guidance again", Source: "Bloomberg", URL: "https://example.com/b", PublishedAt: base.Add(30 * time.Minute), Tickers: []string{"CMA"}},
+	}
+
+	fake := &fakeChatClient{response: `{
+		"clusters": [
+			{"id": "event_a", "news_ids": ["n1", "n2"], "primary_news_id": "n1", "confidence": 0}
+		]
+	}`}
+	clusterer := &LLMClusterer{Client: fake, Model: "gemini-2.5-flash"}
+
+	clusters, err := clusterer.BuildClusters(context.Background(), items)
+	if err != nil {
+		t.Fatalf("BuildClusters: %v", err)
+	}
+	if len(clusters) != 1 || clusters[0].Confidence != 0 || !clusters[0].HasConfidence {
+		t.Fatalf("expected an explicit zero confidence, got %+v", clusters)
+	}
+
+	events := DefaultScorer().ScoreClusters(clusters)
+	// Only the source credibility term remains: 0.3 * mean(0.88, 0.9).
+	if len(events) != 1 || events[0].Confidence != 0.267 {
+		t.Fatalf("expected event confidence 0.267, got %+v", events)
+	}
+}
+
+func TestHeuristicClustererSetsConfidence(t *testing.T) {
+	base := time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)
+	items := []NewsItem{
+		{ID: "n1", Headline: "Company A cuts guidance", PublishedAt: base, Tickers: []string{"CMA"}},
+		{ID: "n2", Headline: "Company A supplier fire", PublishedAt: base.Add(time.Hour), Tickers: []string{"CMA"}},
+		{ID: "n3", Headline: "Unrelated weather report", PublishedAt: base.Add(2 * time.Hour)},
+	}
+
+	clusters, err := NewHeuristicClusterer(6*time.Hour, 0.45).BuildClusters(context.Background(), items)
+	if err != nil {
+		t.Fatalf("BuildClusters: %v", err)
+	}
+	if len(clusters) != 2 {
+		t.Fatalf("expected 2 clusters, got %d", len(clusters))
+	}
+	if clusters[0].Confidence <= clusters[1].Confidence || clusters[1].Confidence != 0.5 {
+		t.Fatalf("corroborated cluster should be more confident than a singleton: %v vs %v", clusters[0].Confidence, clusters[1].Confidence)
+	}
+}
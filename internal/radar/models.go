@@ -12,19 +12,30 @@ type NewsItem struct {
 	URL           string    `json:"url"`
 	Language      string    `json:"language"`
 	PublishedAt   time.Time `json:"published_at"`
+	IngestedAt    time.Time `json:"ingested_at"`
 	Tickers       []string  `json:"tickers"`
 	Entities      []string  `json:"entities"`
 	Country       string    `json:"country"`
 	Category      string    `json:"category"`
 	Sentiment     float64   `json:"sentiment"`
 	ImportanceTag string    `json:"importance_tag"`
+	// Stale marks items accepted despite being older than the ingest max age.
+	Stale bool `json:"stale,omitempty"`
+	// RevisedAt is when this version arrived if it corrected an earlier one;
+	// IngestedAt keeps the first ingest.
+	RevisedAt time.Time `json:"-"`
+	// Revisions holds the superseded versions, oldest first.
+	Revisions []NewsItem `json:"-"`
 }
 
 // Event represents an aggregated hot news candidate with scoring metadata.
 type Event struct {
+	ID         string          `json:"id"`
 	DedupGroup string          `json:"dedup_group"`
 	Headline   string          `json:"headline"`
 	Hotness    float64         `json:"hotness"`
+	Confidence float64         `json:"confidence"`
+	Noise      bool            `json:"noise,omitempty"`
 	WhyNow     string          `json:"why_now"`
 	Entities   []string        `json:"entities"`
 	Tickers    []string        `json:"tickers"`
@@ -39,6 +50,7 @@ type SourceRef struct {
 	Source    string    `json:"source"`
 	URL       string    `json:"url"`
 	Published time.Time `json:"published"`
+	Stale     bool      `json:"stale,omitempty"`
 }
 
 // TimelineEntry captures the key updates within an event cluster.
@@ -59,8 +71,21 @@ type Draft struct {
 
 // QueryParams encapsulates the timeframe and request configuration provided by the user.
 type QueryParams struct {
-	From     time.Time
-	To       time.Time
-	Limit    int
-	Language string
+	From  time.Time
+	To    time.Time
+	Limit int
+	// Unlimited returns every ranked event and ignores Limit, for callers that
+	// filter events themselves before truncating.
+	Unlimited bool
+	Language  string
+	// AsOf, when set, reconstructs the radar as of that moment: items ingested
+	// after it are ignored.
+	AsOf time.Time
+}
+
+// RunMeta describes how a pipeline run was produced.
+type RunMeta struct {
+	// Degradations lists the ways a historical reconstruction may differ from
+	// what the radar actually showed at AsOf.
+	Degradations []string `json:"degradations,omitempty"`
 }
@@ -0,0 +1,226 @@
+package radar
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrInvalidItem is returned by Normalize when an item cannot be accepted.
+var ErrInvalidItem = errors.New("radar: invalid news item")
+
+// NormalizeOptions tunes Normalize for a particular caller.
+type NormalizeOptions struct {
+	// Strict turns recoverable problems (malformed URL, future timestamp,
+	// out-of-range sentiment) into errors instead of warnings.
+	Strict bool
+	// DefaultSource and DefaultLanguage fill empty fields. The language is
+	// only used when it cannot be detected from the text.
+	DefaultSource   string
+	DefaultLanguage string
+	// FutureTolerance is how far PublishedAt may run ahead of Now before it is
+	// clamped (or rejected in strict mode). Zero means five minutes.
+	FutureTolerance time.Duration
+	// MaxAge, when positive, bounds how old PublishedAt may be. Older items are
+	// flagged Stale with a warning, or rejected when RejectStale is set.
+	MaxAge      time.Duration
+	RejectStale bool
+	// Now overrides the reference time; nil means time.Now.
+	Now func() time.Time
+}
+
+// Warning describes a correction Normalize applied to an item.
+type Warning struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Normalize applies the shared cleanup every source should perform: trimming,
+// ticker/entity deduplication, language detection, URL canonicalisation and
+// future-date clamping. It returns the cleaned item together with the
+// corrections made; errors wrap ErrInvalidItem.
+func Normalize(item NewsItem, opts NormalizeOptions) (NewsItem, []Warning, error) {
+	var warnings []Warning
+	warn := func(field, format string, args ...any) error {
+		msg := fmt.Sprintf(format, args...)
+		if opts.Strict {
+			return fmt.Errorf("%w: %s: %s", ErrInvalidItem, field, msg)
+		}
+		warnings = append(warnings, Warning{Field: field, Message: msg})
+		return nil
+	}
+
+	item.ID = strings.TrimSpace(item.ID)
+	item.Headline = strings.TrimSpace(item.Headline)
+	item.Summary = strings.TrimSpace(item.Summary)
+	item.Body = strings.TrimSpace(item.Body)
+	item.Source = strings.TrimSpace(item.Source)
+	item.URL = strings.TrimSpace(item.URL)
+	item.Language = strings.ToLower(strings.TrimSpace(item.Language))
+	item.Country = strings.TrimSpace(item.Country)
+	item.Category = strings.TrimSpace(item.Category)
+	item.ImportanceTag = strings.TrimSpace(item.ImportanceTag)
+
+	if item.Headline == "" || item.URL == "" {
+		return NewsItem{}, nil, fmt.Errorf("%w: headline and url are required", ErrInvalidItem)
+	}
+
+	if canonical, err := canonicalURL(item.URL); err != nil {
+		if werr := warn("url", "%v", err); werr != nil {
+			return NewsItem{}, nil, werr
+		}
+	} else if canonical != item.URL {
+		item.URL = canonical
+	}
+
+	if item.Source == "" {
+		item.Source = opts.DefaultSource
+	}
+
+	if item.Language == "" {
+		if lang := detectLanguage(item.Headline + " " + item.Summary); lang != "" {
+			item.Language = lang
+		} else {
+			item.Language = strings.ToLower(opts.DefaultLanguage)
+		}
+		if item.Language != "" {
+			warnings = append(warnings, Warning{Field: "language", Message: "assumed " + item.Language})
+		}
+	}
+
+	item.Tickers = normalizeTokens(item.Tickers, strings.ToUpper)
+	item.Entities = normalizeTokens(item.Entities, nil)
+
+	if item.Sentiment < -1 || item.Sentiment > 1 {
+		if err := warn("sentiment", "%v outside [-1, 1]", item.Sentiment); err != nil {
+			return NewsItem{}, nil, err
+		}
+		item.Sentiment = clampSentiment(item.Sentiment)
+	}
+
+	if !item.PublishedAt.IsZero() {
+		now := time.Now
+		if opts.Now != nil {
+			now = opts.Now
+		}
+		tolerance := opts.FutureTolerance
+		if tolerance <= 0 {
+			tolerance = 5 * time.Minute
+		}
+		ref := now().UTC()
+		if item.PublishedAt.After(ref.Add(tolerance)) {
+			if err := warn("published_at", "%s is in the future; clamped to %s", item.PublishedAt.Format(time.RFC3339), ref.Format(time.RFC3339)); err != nil {
+				return NewsItem{}, nil, err
+			}
+			item.PublishedAt = ref
+		}
+		if opts.MaxAge > 0 && ref.Sub(item.PublishedAt) > opts.MaxAge {
+			msg := fmt.Sprintf("%s is older than the maximum age of %s", item.PublishedAt.Format(time.RFC3339), opts.MaxAge)
+			if opts.RejectStale {
+				return NewsItem{}, nil, fmt.Errorf("%w: published_at: %s", ErrInvalidItem, msg)
+			}
+			warnings = append(warnings, Warning{Field: "published_at", Message: msg + "; flagged stale"})
+			item.Stale = true
+		}
+	}
+
+	return item, warnings, nil
+}
+
+func canonicalURL(raw string) (string, error) {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("malformed url: %w", err)
+	}
+	scheme := strings.ToLower(parsed.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return "", fmt.Errorf("unsupported url scheme %q", parsed.Scheme)
+	}
+	if parsed.Host == "" {
+		return "", errors.New("url has no host")
+	}
+
+	parsed.Scheme = scheme
+	host := strings.ToLower(parsed.Hostname())
+	port := parsed.Port()
+	if (scheme == "http" && port == "80") || (scheme == "https" && port == "443") {
+		port = ""
+	}
+	parsed.Host = host
+	if port != "" {
+		parsed.Host = host + ":" + port
+	}
+	parsed.Fragment = ""
+	parsed.RawFragment = ""
+
+	if parsed.RawQuery != "" {
+		query := parsed.Query()
+		for key := range query {
+			if strings.HasPrefix(strings.ToLower(key), "utm_") {
+				query.Del(key)
+			}
+		}
+		parsed.RawQuery = query.Encode()
+	}
+
+	return parsed.String(), nil
+}
+
+func detectLanguage(text string) string {
+	var latin, cyrillic int
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+	switch {
+	case cyrillic == 0 && latin == 0:
+		return ""
+	case cyrillic >= latin:
+		return "ru"
+	default:
+		return "en"
+	}
+}
+
+// normalizeTokens trims values, drops empties and removes case-insensitive
+// duplicates, optionally rewriting each kept value with transform.
+func normalizeTokens(values []string, transform func(string) string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	seen := make(map[string]struct{}, len(values))
+	var out []string
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if transform != nil {
+			v = transform(v)
+		}
+		key := strings.ToUpper(v)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		out = append(out, v)
+	}
+	return out
+}
+
+func clampSentiment(v float64) float64 {
+	if v < -1 {
+		return -1
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
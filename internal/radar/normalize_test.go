@@ -0,0 +1,264 @@
+package radar
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNormalize(t *testing.T) {
+	now := time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	base := NewsItem{
+		ID:          "n1",
+		Headline:    "Company A cuts guidance",
+		URL:         "https://example.com/a",
+		Source:      "Reuters",
+		Language:    "en",
+		PublishedAt: now.Add(-time.Hour),
+	}
+	with := func(mutate func(*NewsItem)) NewsItem {
+		item := base
+		mutate(&item)
+		return item
+	}
+
+	tests := []struct {
+		name         string
+		item         NewsItem
+		opts         NormalizeOptions
+		want         NewsItem
+		wantWarnings []string
+		wantErr      bool
+	}{
+		{
+			name: "clean item passes through",
+			item: base,
+			want: base,
+		},
+		{
+			name: "trims text fields",
+			item: with(func(n *NewsItem) {
+				n.ID = " n1 "
+				n.Headline = "  Company A cuts guidance\n"
+				n.Source = " Reuters "
+				n.URL = " https://example.com/a "
+				n.Language = " EN "
+				n.ImportanceTag = " guidance_cut "
+			}),
+			want: with(func(n *NewsItem) { n.ImportanceTag = "guidance_cut" }),
+		},
+		{
+			name:    "missing headline",
+			item:    with(func(n *NewsItem) { n.Headline = "   " }),
+			wantErr: true,
+		},
+		{
+			name:    "missing url",
+			item:    with(func(n *NewsItem) { n.URL = "" }),
+			wantErr: true,
+		},
+		{
+			name: "dedupes and uppercases tickers",
+			item: with(func(n *NewsItem) { n.Tickers = []string{" ntch", "NTCH", "", "^ndx", "Ntch "} }),
+			want: with(func(n *NewsItem) { n.Tickers = []string{"NTCH", "^NDX"} }),
+		},
+		{
+			name: "single ticker is still cleaned",
+			item: with(func(n *NewsItem) { n.Tickers = []string{" sber "} }),
+			want: with(func(n *NewsItem) { n.Tickers = []string{"SBER"} }),
+		},
+		{
+			name: "dedupes entities case-insensitively keeping first spelling",
+			item: with(func(n *NewsItem) { n.Entities = []string{"NordTech", " nordtech", "Taiwan", " "} }),
+			want: with(func(n *NewsItem) { n.Entities = []string{"NordTech", "Taiwan"} }),
+		},
+		{
+			name: "blank token lists become nil",
+			item: with(func(n *NewsItem) { n.Tickers = []string{" ", ""}; n.Entities = []string{} }),
+			want: base,
+		},
+		{
+			name: "canonicalizes url",
+			item: with(func(n *NewsItem) { n.URL = "HTTPS://Example.COM:443/a?utm_source=x&id=5&UTM_medium=y#top" }),
+			want: with(func(n *NewsItem) { n.URL = "https://example.com/a?id=5" }),
+		},
+		{
+			name: "keeps non-default port",
+			item: with(func(n *NewsItem) { n.URL = "http://example.com:8080/a" }),
+			want: with(func(n *NewsItem) { n.URL = "http://example.com:8080/a" }),
+		},
+		{
+			name:         "malformed url warns in lenient mode",
+			item:         with(func(n *NewsItem) { n.URL = "example.com/a" }),
+			want:         with(func(n *NewsItem) { n.URL = "example.com/a" }),
+			wantWarnings: []string{"url"},
+		},
+		{
+			name:    "malformed url rejected in strict mode",
+			item:    with(func(n *NewsItem) { n.URL = "ftp://example.com/a" }),
+			opts:    NormalizeOptions{Strict: true},
+			wantErr: true,
+		},
+		{
+			name: "fills default source",
+			item: with(func(n *NewsItem) { n.Source = "" }),
+			opts: NormalizeOptions{DefaultSource: "ingest"},
+			want: with(func(n *NewsItem) { n.Source = "ingest" }),
+		},
+		{
+			name:         "detects russian",
+			item:         with(func(n *NewsItem) { n.Language = ""; n.Headline = "Компания A снижает прогноз" }),
+			opts:         NormalizeOptions{DefaultLanguage: "en"},
+			want:         with(func(n *NewsItem) { n.Language = "ru"; n.Headline = "Компания A снижает прогноз" }),
+			wantWarnings: []string{"language"},
+		},
+		{
+			name:         "detects english",
+			item:         with(func(n *NewsItem) { n.Language = "" }),
+			opts:         NormalizeOptions{DefaultLanguage: "ru"},
+			want:         base,
+			wantWarnings: []string{"language"},
+		},
+		{
+			name:         "falls back to default language",
+			item:         with(func(n *NewsItem) { n.Language = ""; n.Headline = "2025 — 42%" }),
+			opts:         NormalizeOptions{DefaultLanguage: "EN"},
+			want:         with(func(n *NewsItem) { n.Headline = "2025 — 42%" }),
+			wantWarnings: []string{"language"},
+		},
+		{
+			name: "keeps explicit language",
+			item: with(func(n *NewsItem) { n.Language = "de" }),
+			want: with(func(n *NewsItem) { n.Language = "de" }),
+		},
+		{
+			name:         "clamps sentiment",
+			item:         with(func(n *NewsItem) { n.Sentiment = -3 }),
+			want:         with(func(n *NewsItem) { n.Sentiment = -1 }),
+			wantWarnings: []string{"sentiment"},
+		},
+		{
+			name:    "rejects sentiment in strict mode",
+			item:    with(func(n *NewsItem) { n.Sentiment = 1.5 }),
+			opts:    NormalizeOptions{Strict: true},
+			wantErr: true,
+		},
+		{
+			name:         "clamps future timestamp",
+			item:         with(func(n *NewsItem) { n.PublishedAt = now.Add(2 * time.Hour) }),
+			want:         with(func(n *NewsItem) { n.PublishedAt = now }),
+			wantWarnings: []string{"published_at"},
+		},
+		{
+			name: "tolerates small clock skew",
+			item: with(func(n *NewsItem) { n.PublishedAt = now.Add(time.Minute) }),
+			want: with(func(n *NewsItem) { n.PublishedAt = now.Add(time.Minute) }),
+		},
+		{
+			name: "custom future tolerance",
+			item: with(func(n *NewsItem) { n.PublishedAt = now.Add(time.Hour) }),
+			opts: NormalizeOptions{FutureTolerance: 2 * time.Hour},
+			want: with(func(n *NewsItem) { n.PublishedAt = now.Add(time.Hour) }),
+		},
+		{
+			name:    "rejects future timestamp in strict mode",
+			item:    with(func(n *NewsItem) { n.PublishedAt = now.Add(24 * time.Hour) }),
+			opts:    NormalizeOptions{Strict: true},
+			wantErr: true,
+		},
+		{
+			name:         "flags item older than max age",
+			item:         with(func(n *NewsItem) { n.PublishedAt = now.Add(-30 * 24 * time.Hour) }),
+			opts:         NormalizeOptions{MaxAge: 7 * 24 * time.Hour},
+			want:         with(func(n *NewsItem) { n.PublishedAt = now.Add(-30 * 24 * time.Hour); n.Stale = true }),
+			wantWarnings: []string{"published_at"},
+		},
+		{
+			name: "accepts item within max age",
+			item: base,
+			opts: NormalizeOptions{MaxAge: 7 * 24 * time.Hour, RejectStale: true},
+			want: base,
+		},
+		{
+			name:    "rejects item older than max age",
+			item:    with(func(n *NewsItem) { n.PublishedAt = now.Add(-30 * 24 * time.Hour) }),
+			opts:    NormalizeOptions{MaxAge: 7 * 24 * time.Hour, RejectStale: true},
+			wantErr: true,
+		},
+		{
+			name: "leaves zero timestamp for the caller",
+			item: with(func(n *NewsItem) { n.PublishedAt = time.Time{} }),
+			want: with(func(n *NewsItem) { n.PublishedAt = time.Time{} }),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.opts.Now == nil {
+				tc.opts.Now = clock
+			}
+			got, warnings, err := Normalize(tc.item, tc.opts)
+			if tc.wantErr {
+				if !errors.Is(err, ErrInvalidItem) {
+					t.Fatalf("expected ErrInvalidItem, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("item mismatch:\n got  %+v\n want %+v", got, tc.want)
+			}
+			var fields []string
+			for _, w := range warnings {
+				fields = append(fields, w.Field)
+			}
+			if !reflect.DeepEqual(fields, tc.wantWarnings) {
+				t.Errorf("warnings: got %v, want %v", fields, tc.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{input: "2025-10-03T08:00:00Z", want: time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)},
+		{input: "2025-10-03T11:00:00+03:00", want: time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)},
+		{input: "2025-10-03T08:00:00.250Z", want: time.Date(2025, 10, 3, 8, 0, 0, 250e6, time.UTC)},
+		{input: " 2025-10-03T08:00:00Z ", want: time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)},
+		{input: "Fri, 03 Oct 2025 08:00:00 GMT", want: time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)},
+		{input: "Fri, 03 Oct 2025 11:00:00 +0300", want: time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)},
+		{input: "1759478400", want: time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)},
+		{input: "0", wantErr: true},
+		{input: "", wantErr: true},
+		{input: "yesterday", wantErr: true},
+		{input: "2025-10-03", wantErr: true},
+		{input: "1759478400.5", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := ParseTimestamp(tc.input)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tc.want) {
+				t.Fatalf("got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
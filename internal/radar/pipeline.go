@@ -18,41 +18,151 @@ type Pipeline struct {
 	Sources   *SourceRegistry
 	Clusterer ClusterEngine
 	Scorer    Scorer
+	// Shadow optionally evaluates a candidate engine on the same items in the background.
+	Shadow *ShadowRunner
 }
 
 // NewPipeline constructs a new Pipeline.
 func NewPipeline(sources *SourceRegistry, clusterer ClusterEngine, scorer Scorer) (*Pipeline, error) {
 	if sources == nil {
-		return nil, errors.New("pipeline requires sources")
+		return nil, ErrNoSources
 	}
 	return &Pipeline{Sources: sources, Clusterer: clusterer, Scorer: scorer}, nil
 }
 
 // Run executes the end-to-end flow returning the hottest events.
+//
+// Errors are one of the radar taxonomy values: ErrNoSources, *SourceError,
+// *ClusterError, ErrWindowEmpty, or ErrCanceled wrapping the context error.
 func (p *Pipeline) Run(ctx context.Context, params QueryParams) ([]Event, error) {
+	events, _, err := p.RunWithMeta(ctx, params)
+	return events, err
+}
+
+// RunWithMeta behaves like Run and additionally reports how the result was
+// produced, e.g. the limits of an as_of reconstruction.
+func (p *Pipeline) RunWithMeta(ctx context.Context, params QueryParams) ([]Event, RunMeta, error) {
 	if params.Limit <= 0 {
 		params.Limit = 5
 	}
+	items, clusters, meta, err := p.cluster(ctx, params)
+	if err != nil {
+		return nil, meta, err
+	}
+	p.Shadow.Observe(items, clusters)
+	scorer := p.Scorer
+	if scorer.ReferenceTime.IsZero() {
+		scorer.ReferenceTime = params.To
+	}
+	events := scorer.ScoreClusters(clusters)
+
+	if !params.Unlimited && len(events) > params.Limit {
+		events = events[:params.Limit]
+	}
+
+	return events, meta, nil
+}
+
+// Clusters runs only the fetch and clustering stages and returns the raw
+// clusters, e.g. for debugging engine output. Errors match Run.
+func (p *Pipeline) Clusters(ctx context.Context, params QueryParams) ([]Cluster, error) {
+	_, clusters, _, err := p.cluster(ctx, params)
+	return clusters, err
+}
+
+func (p *Pipeline) cluster(ctx context.Context, params QueryParams) ([]NewsItem, []Cluster, RunMeta, error) {
+	var meta RunMeta
 	items, err := p.Sources.FetchAll(ctx, params.From, params.To)
 	if err != nil {
-		return nil, err
+		if ctx.Err() != nil {
+			return nil, nil, meta, canceled(err)
+		}
+		return nil, nil, meta, err
 	}
+	if !params.AsOf.IsZero() {
+		items, meta = filterIngestedBefore(items, params.AsOf)
+	}
+	// Sources may return items for an earlier version's timestamp; keep only
+	// those whose selected version falls in the window.
+	items = filterWindow(items, params.From, params.To)
 	if params.Language != "" {
 		items = filterLanguage(items, params.Language)
 	}
+	if len(items) == 0 {
+		return nil, nil, meta, ErrWindowEmpty
+	}
 
 	clusters, err := p.Clusterer.BuildClusters(ctx, items)
 	if err != nil {
-		return nil, err
+		var clusterErr *ClusterError
+		if !errors.As(err, &clusterErr) {
+			err = &ClusterError{Engine: engineName(p.Clusterer), Err: err}
+		}
+		if ctx.Err() != nil {
+			return nil, nil, meta, canceled(err)
+		}
+		return nil, nil, meta, err
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, nil, meta, canceled(ctxErr)
 	}
 	fmt.Println("Pipeline: formed", len(clusters), "clusters from", len(items), "items")
-	events := p.Scorer.ScoreClusters(clusters)
+	return items, clusters, meta, nil
+}
 
-	if len(events) > params.Limit {
-		events = events[:params.Limit]
+// filterIngestedBefore drops items ingested after asOf and replaces corrected
+// items with the version that was current at asOf. Items without an ingest
+// timestamp (static datasets) are kept and reported as a degradation.
+func filterIngestedBefore(items []NewsItem, asOf time.Time) ([]NewsItem, RunMeta) {
+	meta := RunMeta{Degradations: []string{
+		"no archived run available; reconstructed with current clustering and scorer weights",
+	}}
+	filtered := items[:0:0]
+	unknown := 0
+	for _, item := range items {
+		if item.IngestedAt.IsZero() {
+			unknown++
+		} else if item.IngestedAt.After(asOf) {
+			continue
+		}
+		filtered = append(filtered, versionAt(item, asOf))
+	}
+	if unknown > 0 {
+		meta.Degradations = append(meta.Degradations, fmt.Sprintf("%d items have no ingest timestamp and were assumed available at as_of", unknown))
+	}
+	return filtered, meta
+}
+
+// versionAt returns the revision of item that was current at asOf, without its
+// history. Revisions are ordered oldest first, so the first ingest is the fallback.
+func versionAt(item NewsItem, asOf time.Time) NewsItem {
+	current := item
+	for idx := len(item.Revisions) - 1; idx >= 0 && current.RevisedAt.After(asOf); idx-- {
+		current = item.Revisions[idx]
 	}
+	current.Revisions = nil
+	return current
+}
 
-	return events, nil
+func filterWindow(items []NewsItem, from, to time.Time) []NewsItem {
+	filtered := items[:0:0]
+	for _, item := range items {
+		if inWindow(item.PublishedAt, from, to) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+func engineName(engine ClusterEngine) string {
+	switch engine.(type) {
+	case *LLMClusterer:
+		return "llm"
+	case HeuristicClusterer, *HeuristicClusterer:
+		return "heuristic"
+	default:
+		return fmt.Sprintf("%T", engine)
+	}
 }
 
 func filterLanguage(items []NewsItem, lang string) []NewsItem {
@@ -89,6 +199,7 @@ func DefaultScorer() Scorer {
 			"management_comment": 0.55,
 			"positioning":        0.58,
 		},
+		NoiseCap: 0.35,
 	}
 }
 
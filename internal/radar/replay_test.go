@@ -0,0 +1,124 @@
+package radar
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReplaySourceExposesItemsProgressively(t *testing.T) {
+	base := time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)
+	items := []NewsItem{
+		{ID: "n3", Headline: "Third", PublishedAt: base.Add(3 * time.Hour)},
+		{ID: "n1", Headline: "First", PublishedAt: base},
+		{ID: "n2", Headline: "Second", PublishedAt: base.Add(90 * time.Minute)},
+	}
+
+	wall := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewReplayController(base, base.Add(3*time.Hour), 60, func() time.Time { return wall })
+	source, err := NewReplaySource("replay", items, clock)
+	if err != nil {
+		t.Fatalf("replay source: %v", err)
+	}
+
+	exposed := func() int {
+		t.Helper()
+		got, err := source.Fetch(context.Background(), base.Add(-time.Hour), base.Add(24*time.Hour))
+		if err != nil {
+			t.Fatalf("fetch: %v", err)
+		}
+		return len(got)
+	}
+
+	if n := exposed(); n != 1 {
+		t.Fatalf("paused at start: expected 1 item, got %d", n)
+	}
+
+	wall = wall.Add(time.Minute)
+	if n := exposed(); n != 1 {
+		t.Fatalf("paused clock should not advance, got %d items", n)
+	}
+
+	clock.Start()
+	wall = wall.Add(time.Minute)
+	if got := clock.Now(); !got.Equal(base.Add(time.Hour)) {
+		t.Fatalf("expected virtual time %s, got %s", base.Add(time.Hour), got)
+	}
+	if n := exposed(); n != 1 {
+		t.Fatalf("after one virtual hour: expected 1 item, got %d", n)
+	}
+
+	wall = wall.Add(30 * time.Second)
+	if n := exposed(); n != 2 {
+		t.Fatalf("after 90 virtual minutes: expected 2 items, got %d", n)
+	}
+
+	clock.Pause()
+	wall = wall.Add(time.Hour)
+	if n := exposed(); n != 2 {
+		t.Fatalf("pause should freeze exposure, got %d items", n)
+	}
+
+	clock.Seek(base.Add(10 * time.Hour))
+	if got := clock.Now(); !got.Equal(base.Add(3 * time.Hour)) {
+		t.Fatalf("seek should clamp to replay end, got %s", got)
+	}
+	if n := exposed(); n != 3 {
+		t.Fatalf("after seeking to end: expected 3 items, got %d", n)
+	}
+}
+
+func TestReplayControllerSpeedChangeKeepsPosition(t *testing.T) {
+	base := time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)
+	wall := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewReplayController(base, time.Time{}, 10, func() time.Time { return wall })
+
+	clock.Start()
+	wall = wall.Add(time.Minute)
+	if err := clock.SetSpeed(120); err != nil {
+		t.Fatalf("set speed: %v", err)
+	}
+	if got := clock.Now(); !got.Equal(base.Add(10 * time.Minute)) {
+		t.Fatalf("speed change should not jump, got %s", got)
+	}
+
+	wall = wall.Add(time.Minute)
+	if got := clock.Now(); !got.Equal(base.Add(130 * time.Minute)) {
+		t.Fatalf("expected virtual time to advance at new speed, got %s", got)
+	}
+
+	if err := clock.SetSpeed(0); err == nil {
+		t.Fatalf("expected error for non-positive speed")
+	}
+}
+
+func TestLoadReplayItemsUsesTimestampOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "replay.json")
+	raw := `[
+		{"id": "n1", "headline": "Zone-less", "url": "https://example.com/a", "published_at": "2025-10-03T11:00:00"},
+		{"id": "n2", "headline": "Canonical", "url": "https://example.com/b", "published_at": "2025-10-03T09:00:00Z"}
+	]`
+	if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	moscow := time.FixedZone("MSK", 3*60*60)
+	items, first, last, err := LoadReplayItems(path, TimestampOptions{DefaultZone: moscow})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	want := time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)
+	if len(items) != 2 || !first.Equal(want) || !last.Equal(want.Add(time.Hour)) {
+		t.Fatalf("expected zone-less time read in the default zone, got %d items from %s to %s", len(items), first, last)
+	}
+
+	items, _, _, err = LoadReplayItems(path, TimestampOptions{Strict: true})
+	if err != nil {
+		t.Fatalf("load strict: %v", err)
+	}
+	if len(items) != 1 || items[0].ID != "n2" {
+		t.Fatalf("strict timestamps should skip the zone-less record, got %+v", items)
+	}
+}
@@ -4,12 +4,24 @@ import (
 	"math"
 	"sort"
 	"strings"
+	"time"
 )
 
 // Scorer evaluates clusters and returns Event representations sorted by hotness.
 type Scorer struct {
 	SourceWeights map[string]float64
 	TagWeights    map[string]float64
+	// NoiseCap caps the hotness of clusters flagged as noise; zero leaves them uncapped.
+	NoiseCap float64
+	// ExcludeNoise drops noise clusters from the ranking entirely.
+	ExcludeNoise bool
+	// TagHalfLife halves how far an item's importance tag weight sits above the
+	// untagged weight for every TagHalfLife of age, so old backfilled news does
+	// not resurface as hot; zero disables the decay.
+	TagHalfLife time.Duration
+	// ReferenceTime is the moment ages are measured from; zero means time.Now.
+	// The pipeline sets it to the end of the query window.
+	ReferenceTime time.Time
 }
 
 // ScoreClusters computes hotness metrics and returns sorted events.
@@ -20,6 +32,9 @@ func (s Scorer) ScoreClusters(clusters []Cluster) []Event {
 
 	events := make([]Event, 0, len(clusters))
 	for _, cluster := range clusters {
+		if cluster.Noise && s.ExcludeNoise {
+			continue
+		}
 		event := s.buildEvent(cluster)
 		if event.Hotness <= 0 {
 			continue
@@ -57,6 +72,7 @@ func (s Scorer) buildEvent(cluster Cluster) Event {
 			Source:    item.Source,
 			URL:       item.URL,
 			Published: item.PublishedAt,
+			Stale:     item.Stale,
 		})
 		for _, ticker := range item.Tickers {
 			t := strings.ToUpper(ticker)
@@ -135,10 +151,23 @@ func (s Scorer) buildEvent(cluster Cluster) Event {
 	draft := buildDraft(cluster, entities, tickers, sources, whyNow)
 	timeline := buildTimeline(cluster)
 
+	if cluster.Noise && s.NoiseCap > 0 {
+		hotness = math.Min(hotness, s.NoiseCap)
+	}
+
+	clusterConfidence := cluster.Confidence
+	if !cluster.HasConfidence {
+		clusterConfidence = heuristicConfidence(items)
+	}
+	confidence := 0.7*clamp01(clusterConfidence) + 0.3*sourceScore
+
 	return Event{
+		ID:         EventID(cluster),
 		DedupGroup: cluster.ID,
 		Headline:   cluster.Primary.Headline,
 		Hotness:    roundTo(hotness, 3),
+		Confidence: roundTo(clamp01(confidence), 3),
+		Noise:      cluster.Noise,
 		WhyNow:     whyNow,
 		Entities:   entities,
 		Tickers:    tickers,
@@ -163,19 +192,45 @@ func (s Scorer) averageSourceWeight(items []NewsItem) float64 {
 	return math.Min(1.0, total/float64(len(items)))
 }
 
+// neutralTagWeight is the tag weight of clusters without a known importance tag.
+const neutralTagWeight = 0.45
+
 func (s Scorer) tagWeight(items []NewsItem) float64 {
 	var best float64
 	for _, item := range items {
-		if w, ok := s.TagWeights[item.ImportanceTag]; ok && w > best {
+		w, ok := s.TagWeights[item.ImportanceTag]
+		if !ok || w <= 0 {
+			continue
+		}
+		// Decay toward the neutral weight, so an old tag fades to "untagged"
+		// rather than below it.
+		if w = neutralTagWeight + (w-neutralTagWeight)*s.tagDecay(item.PublishedAt); w > best {
 			best = w
 		}
 	}
 	if best == 0 {
-		return 0.45
+		return neutralTagWeight
 	}
 	return best
 }
 
+// tagDecay returns the factor applied to the distance between an item's tag
+// weight and neutralTagWeight for an item published at ts.
+func (s Scorer) tagDecay(ts time.Time) float64 {
+	if s.TagHalfLife <= 0 {
+		return 1
+	}
+	ref := s.ReferenceTime
+	if ref.IsZero() {
+		ref = time.Now()
+	}
+	age := ref.Sub(ts)
+	if age <= 0 {
+		return 1
+	}
+	return math.Pow(0.5, float64(age)/float64(s.TagHalfLife))
+}
+
 func (s Scorer) composeWhyNow(coverage, reach, velocity, sourceScore float64) string {
 	var notes []string
 	if coverage > 1 {
@@ -0,0 +1,71 @@
+package radar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScorerTagDecayRanksFreshAboveOld(t *testing.T) {
+	now := time.Date(2025, 10, 24, 12, 0, 0, 0, time.UTC)
+	singleton := func(id string, published time.Time) Cluster {
+		item := NewsItem{
+			ID:            id,
+			Headline:      "NordTech cuts guidance",
+			Source:        "Reuters",
+			PublishedAt:   published,
+			Tickers:       []string{"NTCH"},
+			ImportanceTag: "guidance_cut",
+		}
+		return Cluster{ID: id, Items: []NewsItem{item}, Primary: item, StartTime: published, EndTime: published}
+	}
+	old := singleton("old", now.Add(-21*24*time.Hour))
+	fresh := singleton("fresh", now.Add(-time.Hour))
+
+	scorer := DefaultScorer()
+	scorer.ReferenceTime = now
+	if oldHot, freshHot := scorer.buildEvent(old).Hotness, scorer.buildEvent(fresh).Hotness; oldHot != freshHot {
+		t.Fatalf("without decay identical tags should score the same, got old %.3f fresh %.3f", oldHot, freshHot)
+	}
+
+	scorer.TagHalfLife = 72 * time.Hour
+	events := scorer.ScoreClusters([]Cluster{old, fresh})
+	if len(events) != 2 || events[0].DedupGroup != "fresh" {
+		t.Fatalf("expected the fresh event first, got %+v", events)
+	}
+	if events[0].Hotness <= events[1].Hotness {
+		t.Fatalf("expected decay to lower the old event, got fresh %.3f old %.3f", events[0].Hotness, events[1].Hotness)
+	}
+
+	if decay := scorer.tagDecay(now.Add(-72 * time.Hour)); decay < 0.499 || decay > 0.501 {
+		t.Fatalf("expected half weight after one half-life, got %.3f", decay)
+	}
+}
+
+func TestScorerTagDecayNeverDropsBelowUntagged(t *testing.T) {
+	now := time.Date(2025, 10, 24, 12, 0, 0, 0, time.UTC)
+	published := now.Add(-21 * 24 * time.Hour)
+	cluster := func(tag string) Cluster {
+		item := NewsItem{
+			ID:            "n1",
+			Headline:      "NordTech cuts guidance",
+			Source:        "Reuters",
+			PublishedAt:   published,
+			Tickers:       []string{"NTCH"},
+			ImportanceTag: tag,
+		}
+		return Cluster{ID: "n1", Items: []NewsItem{item}, Primary: item, StartTime: published, EndTime: published}
+	}
+
+	scorer := DefaultScorer()
+	scorer.ReferenceTime = now
+	scorer.TagHalfLife = 72 * time.Hour
+	untagged := scorer.buildEvent(cluster("")).Hotness
+	for tag := range scorer.TagWeights {
+		if tagged := scorer.buildEvent(cluster(tag)).Hotness; tagged < untagged {
+			t.Errorf("old %s item scored %.3f, below the untagged %.3f", tag, tagged, untagged)
+		}
+	}
+	if w := scorer.tagWeight(cluster("guidance_cut").Items); w < neutralTagWeight || w > neutralTagWeight+0.01 {
+		t.Fatalf("expected a 21-day-old tag to approach the neutral weight, got %.4f", w)
+	}
+}
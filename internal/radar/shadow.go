@@ -0,0 +1,283 @@
+package radar
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const shadowTopN = 5
+
+// ShadowComparison captures how a candidate clustering engine compared with the
+// serving engine on one sampled run.
+type ShadowComparison struct {
+	At                time.Time `json:"at"`
+	Items             int       `json:"items"`
+	PrimaryClusters   int       `json:"primary_clusters"`
+	CandidateClusters int       `json:"candidate_clusters"`
+	// PairwiseAgreement is the Rand index over item pairs: the share of pairs both
+	// engines treat the same way (together or apart).
+	PairwiseAgreement float64 `json:"pairwise_agreement"`
+	// TopOverlap is the share of the primary top-5 events whose primary item also
+	// lands in one of the candidate's top-5 events.
+	TopOverlap float64       `json:"top_overlap"`
+	Duration   time.Duration `json:"duration_ns"`
+	Error      string        `json:"error,omitempty"`
+}
+
+// ShadowSummary aggregates the rolling window of comparisons.
+type ShadowSummary struct {
+	Engine               string            `json:"engine"`
+	SampleRate           float64           `json:"sample_rate"`
+	Runs                 int               `json:"runs"`
+	Failures             int               `json:"failures"`
+	Skipped              int64             `json:"skipped"`
+	AvgClusterDelta      float64           `json:"avg_cluster_delta"`
+	AvgPairwiseAgreement float64           `json:"avg_pairwise_agreement"`
+	AvgTopOverlap        float64           `json:"avg_top_overlap"`
+	Last                 *ShadowComparison `json:"last,omitempty"`
+}
+
+// ShadowSink receives every completed comparison, e.g. to export metrics.
+type ShadowSink interface {
+	RecordShadow(ShadowComparison)
+}
+
+// ShadowRunner evaluates a candidate clustering engine on the same items the
+// pipeline served, without affecting the primary response: comparisons run in
+// the background, at most one at a time, and failures are only recorded.
+type ShadowRunner struct {
+	Engine     string
+	Candidate  ClusterEngine
+	Scorer     Scorer
+	SampleRate float64
+	Sink       ShadowSink
+	Timeout    time.Duration
+	Window     int
+
+	sample  func() float64
+	busy    atomic.Bool
+	skipped atomic.Int64
+	wg      sync.WaitGroup
+
+	mu      sync.Mutex
+	history []ShadowComparison
+}
+
+// NewShadowRunner builds a runner that samples sampleRate of pipeline runs.
+func NewShadowRunner(engine string, candidate ClusterEngine, scorer Scorer, sampleRate float64) *ShadowRunner {
+	return &ShadowRunner{
+		Engine:     engine,
+		Candidate:  candidate,
+		Scorer:     scorer,
+		SampleRate: sampleRate,
+		Timeout:    time.Minute,
+		Window:     50,
+		sample:     rand.Float64,
+	}
+}
+
+// Observe schedules a comparison against the primary clusters when the run is
+// sampled and no other comparison is in flight. It never blocks on the candidate.
+func (r *ShadowRunner) Observe(items []NewsItem, primary []Cluster) {
+	if r == nil || r.Candidate == nil || len(items) == 0 {
+		return
+	}
+	sample := r.sample
+	if sample == nil {
+		sample = rand.Float64
+	}
+	if r.SampleRate <= 0 || sample() >= r.SampleRate {
+		return
+	}
+	if !r.busy.CompareAndSwap(false, true) {
+		r.skipped.Add(1)
+		return
+	}
+
+	owned := make([]NewsItem, len(items))
+	for idx, item := range items {
+		owned[idx] = cloneNewsItem(item)
+	}
+	served := cloneClusters(primary)
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer r.busy.Store(false)
+		r.record(r.compare(owned, served))
+	}()
+}
+
+// Wait blocks until the in-flight comparison, if any, has finished.
+func (r *ShadowRunner) Wait() {
+	if r != nil {
+		r.wg.Wait()
+	}
+}
+
+// Summary returns the rolling comparison summary.
+func (r *ShadowRunner) Summary() ShadowSummary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	summary := ShadowSummary{Engine: r.Engine, SampleRate: r.SampleRate, Skipped: r.skipped.Load()}
+	for _, cmp := range r.history {
+		if cmp.Error != "" {
+			summary.Failures++
+			continue
+		}
+		summary.Runs++
+		delta := float64(cmp.CandidateClusters - cmp.PrimaryClusters)
+		summary.AvgClusterDelta += delta
+		summary.AvgPairwiseAgreement += cmp.PairwiseAgreement
+		summary.AvgTopOverlap += cmp.TopOverlap
+	}
+	if summary.Runs > 0 {
+		n := float64(summary.Runs)
+		summary.AvgClusterDelta = roundTo(summary.AvgClusterDelta/n, 3)
+		summary.AvgPairwiseAgreement = roundTo(summary.AvgPairwiseAgreement/n, 3)
+		summary.AvgTopOverlap = roundTo(summary.AvgTopOverlap/n, 3)
+	}
+	if len(r.history) > 0 {
+		last := r.history[len(r.history)-1]
+		summary.Last = &last
+	}
+	return summary
+}
+
+func (r *ShadowRunner) compare(items []NewsItem, served []Cluster) (cmp ShadowComparison) {
+	started := time.Now()
+	cmp = ShadowComparison{At: started.UTC(), Items: len(items), PrimaryClusters: len(served)}
+	defer func() {
+		if rec := recover(); rec != nil {
+			cmp.Error = fmt.Sprintf("candidate panicked: %v", rec)
+		}
+		cmp.Duration = time.Since(started)
+	}()
+
+	primary := clusterAssignment(served)
+	primaryTop := r.topClusters(served)
+
+	ctx := context.Background()
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+
+	clusters, err := r.Candidate.BuildClusters(ctx, items)
+	if err != nil {
+		cmp.Error = err.Error()
+		return cmp
+	}
+
+	candidate := clusterAssignment(clusters)
+	cmp.CandidateClusters = len(clusters)
+	cmp.PairwiseAgreement = roundTo(pairwiseAgreement(primary, candidate), 3)
+	cmp.TopOverlap = roundTo(topOverlap(primaryTop, candidate, r.topClusters(clusters)), 3)
+	return cmp
+}
+
+func (r *ShadowRunner) record(cmp ShadowComparison) {
+	if cmp.Error != "" {
+		log.Printf("ShadowRunner %s: %s", r.Engine, cmp.Error)
+	}
+	r.mu.Lock()
+	r.history = append(r.history, cmp)
+	if window := r.Window; window > 0 && len(r.history) > window {
+		r.history = append([]ShadowComparison(nil), r.history[len(r.history)-window:]...)
+	}
+	r.mu.Unlock()
+
+	if r.Sink != nil {
+		r.Sink.RecordShadow(cmp)
+	}
+}
+
+// topClusters returns the IDs of the primary items of the hottest clusters.
+func (r *ShadowRunner) topClusters(clusters []Cluster) []string {
+	type ranked struct {
+		primary string
+		hotness float64
+	}
+	scored := make([]ranked, 0, len(clusters))
+	for _, cluster := range clusters {
+		if len(cluster.Items) == 0 {
+			continue
+		}
+		scored = append(scored, ranked{primary: cluster.Primary.ID, hotness: r.Scorer.buildEvent(cluster).Hotness})
+	}
+	sort.SliceStable(scored, func(i, j int) bool { return scored[i].hotness > scored[j].hotness })
+	if len(scored) > shadowTopN {
+		scored = scored[:shadowTopN]
+	}
+	out := make([]string, len(scored))
+	for idx, entry := range scored {
+		out[idx] = entry.primary
+	}
+	return out
+}
+
+// clusterAssignment maps each item ID to the ID of the cluster holding it.
+func clusterAssignment(clusters []Cluster) map[string]string {
+	assignment := make(map[string]string)
+	for idx, cluster := range clusters {
+		key := fmt.Sprintf("%d:%s", idx, cluster.ID)
+		for _, item := range cluster.Items {
+			assignment[item.ID] = key
+		}
+	}
+	return assignment
+}
+
+func pairwiseAgreement(a, b map[string]string) float64 {
+	var ids []string
+	for id := range a {
+		if _, ok := b[id]; ok {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) < 2 {
+		return 1
+	}
+
+	var agree, total int
+	for i := 0; i < len(ids); i++ {
+		for j := i + 1; j < len(ids); j++ {
+			sameA := a[ids[i]] == a[ids[j]]
+			sameB := b[ids[i]] == b[ids[j]]
+			if sameA == sameB {
+				agree++
+			}
+			total++
+		}
+	}
+	return float64(agree) / float64(total)
+}
+
+func topOverlap(primaryTop []string, candidate map[string]string, candidateTop []string) float64 {
+	if len(primaryTop) == 0 {
+		return 1
+	}
+	topClusters := make(map[string]struct{}, len(candidateTop))
+	for _, id := range candidateTop {
+		topClusters[candidate[id]] = struct{}{}
+	}
+	var hits int
+	for _, id := range primaryTop {
+		cluster, ok := candidate[id]
+		if !ok {
+			continue
+		}
+		if _, ok := topClusters[cluster]; ok {
+			hits++
+		}
+	}
+	return float64(hits) / float64(len(primaryTop))
+}
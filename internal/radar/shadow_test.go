@@ -0,0 +1,149 @@
+package radar
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// groupingEngine deterministically clusters items by the provided ID groups.
+type groupingEngine struct {
+	groups  [][]string
+	release chan struct{}
+	panics  bool
+}
+
+func (g groupingEngine) BuildClusters(ctx context.Context, items []NewsItem) ([]Cluster, error) {
+	if g.release != nil {
+		<-g.release
+	}
+	if g.panics {
+		panic("candidate exploded")
+	}
+	byID := make(map[string]NewsItem, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+	var clusters []Cluster
+	for idx, group := range g.groups {
+		cluster := Cluster{ID: string(rune('A' + idx))}
+		for _, id := range group {
+			cluster.Items = append(cluster.Items, byID[id])
+		}
+		cluster.Primary = cluster.Items[0]
+		cluster.StartTime = cluster.Primary.PublishedAt
+		cluster.EndTime = cluster.Items[len(cluster.Items)-1].PublishedAt
+		clusters = append(clusters, cluster)
+	}
+	return clusters, nil
+}
+
+func shadowTestPipeline(t *testing.T, primary ClusterEngine, shadow *ShadowRunner) (*Pipeline, QueryParams) {
+	t.Helper()
+	base := time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)
+	ingest := NewIngestSource("ingest")
+	for idx, id := range []string{"a", "b", "c", "d"} {
+		ingest.Add(NewsItem{ID: id, Headline: "Story " + id, URL: "https://example.com/" + id, Source: "Reuters", PublishedAt: base.Add(time.Duration(idx) * time.Minute)})
+	}
+	sources, err := NewSourceRegistry(ingest)
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+	pipeline, err := NewPipeline(sources, primary, DefaultScorer())
+	if err != nil {
+		t.Fatalf("pipeline: %v", err)
+	}
+	pipeline.Shadow = shadow
+	return pipeline, QueryParams{From: base.Add(-time.Hour), To: base.Add(time.Hour), Limit: 10}
+}
+
+func TestShadowRunnerRecordsAgreementMetrics(t *testing.T) {
+	primary := groupingEngine{groups: [][]string{{"a", "b"}, {"c"}, {"d"}}}
+	candidate := groupingEngine{groups: [][]string{{"a"}, {"b"}, {"c", "d"}}}
+	shadow := NewShadowRunner("candidate", candidate, DefaultScorer(), 1)
+
+	pipeline, params := shadowTestPipeline(t, primary, shadow)
+	if _, err := pipeline.Run(context.Background(), params); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	shadow.Wait()
+
+	summary := shadow.Summary()
+	if summary.Runs != 1 || summary.Failures != 0 {
+		t.Fatalf("expected one successful comparison, got %+v", summary)
+	}
+	last := summary.Last
+	if last.PrimaryClusters != 3 || last.CandidateClusters != 3 || last.Items != 4 {
+		t.Errorf("unexpected cluster counts: %+v", last)
+	}
+	// Of the six item pairs, only the four split by both engines agree.
+	if last.PairwiseAgreement != 0.667 {
+		t.Errorf("expected pairwise agreement 0.667, got %v", last.PairwiseAgreement)
+	}
+	if last.TopOverlap != 1 {
+		t.Errorf("expected full top overlap with fewer than five clusters, got %v", last.TopOverlap)
+	}
+}
+
+func TestShadowRunnerIdenticalEnginesAgree(t *testing.T) {
+	engine := groupingEngine{groups: [][]string{{"a", "b"}, {"c", "d"}}}
+	shadow := NewShadowRunner("same", engine, DefaultScorer(), 1)
+
+	pipeline, params := shadowTestPipeline(t, engine, shadow)
+	if _, err := pipeline.Run(context.Background(), params); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	shadow.Wait()
+
+	summary := shadow.Summary()
+	if summary.AvgPairwiseAgreement != 1 || summary.AvgTopOverlap != 1 || summary.AvgClusterDelta != 0 {
+		t.Fatalf("identical engines should fully agree, got %+v", summary)
+	}
+}
+
+func TestTopOverlapCountsSharedTopEvents(t *testing.T) {
+	candidate := map[string]string{"a": "X", "b": "X", "c": "Y", "d": "Z", "e": "W"}
+	got := topOverlap([]string{"a", "b", "c", "e"}, candidate, []string{"a", "d"})
+	if got != 0.5 {
+		t.Fatalf("expected overlap 0.5, got %v", got)
+	}
+}
+
+func TestShadowRunnerDoesNotInterfereWithPrimary(t *testing.T) {
+	release := make(chan struct{})
+	primary := groupingEngine{groups: [][]string{{"a", "b"}, {"c", "d"}}}
+	shadow := NewShadowRunner("slow", groupingEngine{groups: primary.groups, release: release, panics: true}, DefaultScorer(), 1)
+
+	pipeline, params := shadowTestPipeline(t, primary, shadow)
+
+	done := make(chan error, 1)
+	go func() {
+		events, err := pipeline.Run(context.Background(), params)
+		if err == nil && len(events) != 2 {
+			t.Errorf("expected 2 primary events, got %d", len(events))
+		}
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("run: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("primary run blocked on the shadow engine")
+	}
+
+	// A second run while the candidate is still busy is skipped, not queued.
+	if _, err := pipeline.Run(context.Background(), params); err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+
+	close(release)
+	shadow.Wait()
+
+	summary := shadow.Summary()
+	if summary.Failures != 1 || summary.Runs != 0 || summary.Skipped != 1 {
+		t.Fatalf("expected one recorded panic and one skipped run, got %+v", summary)
+	}
+}
@@ -4,11 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"os"
+	"sort"
+	"sync"
 	"time"
 )
 
 // Source defines a pluggable upstream provider capable of fetching news items within a window.
+//
+// Fetch must return items the caller may freely mutate: implementations that keep
+// items in memory must hand out copies (including the Tickers and Entities slices)
+// and must not retain references to the returned slice.
 type Source interface {
 	Name() string
 	Fetch(ctx context.Context, from, to time.Time) ([]NewsItem, error)
@@ -22,7 +29,7 @@ type SourceRegistry struct {
 // NewSourceRegistry builds a registry with the provided sources.
 func NewSourceRegistry(sources ...Source) (*SourceRegistry, error) {
 	if len(sources) == 0 {
-		return nil, errors.New("radar: at least one source is required")
+		return nil, ErrNoSources
 	}
 	return &SourceRegistry{sources: sources}, nil
 }
@@ -32,23 +39,95 @@ func (r *SourceRegistry) Add(source Source) {
 	r.sources = append(r.sources, source)
 }
 
-// FetchAll aggregates items from each registered source.
+// FetchAll aggregates items from each registered source. A failing source is
+// reported as *SourceError; a registry without sources returns ErrNoSources.
+//
+// The returned slice is freshly allocated and owned by the caller. Items are
+// sorted by PublishedAt ascending; ties keep source registration order and are
+// then ordered by item ID.
 func (r *SourceRegistry) FetchAll(ctx context.Context, from, to time.Time) ([]NewsItem, error) {
-	var results []NewsItem
-	for _, src := range r.sources {
+	if r == nil || len(r.sources) == 0 {
+		return nil, ErrNoSources
+	}
+
+	type ranked struct {
+		item   NewsItem
+		source int
+	}
+
+	var collected []ranked
+	for idx, src := range r.sources {
 		items, err := src.Fetch(ctx, from, to)
 		if err != nil {
-			return nil, fmt.Errorf("fetch from %s: %w", src.Name(), err)
+			return nil, &SourceError{Name: src.Name(), Err: err}
+		}
+		for _, item := range items {
+			collected = append(collected, ranked{item: cloneNewsItem(item), source: idx})
+		}
+	}
+
+	sort.SliceStable(collected, func(i, j int) bool {
+		a, b := collected[i], collected[j]
+		if !a.item.PublishedAt.Equal(b.item.PublishedAt) {
+			return a.item.PublishedAt.Before(b.item.PublishedAt)
 		}
-		results = append(results, items...)
+		if a.source != b.source {
+			return a.source < b.source
+		}
+		return a.item.ID < b.item.ID
+	})
+
+	results := make([]NewsItem, len(collected))
+	for idx, entry := range collected {
+		results[idx] = entry.item
 	}
 	return results, nil
 }
 
-// StaticFileSource serves NewsItem documents from a JSON file.
+// cloneNewsItem returns a copy of item that shares no slices with the original.
+func cloneNewsItem(item NewsItem) NewsItem {
+	if item.Tickers != nil {
+		item.Tickers = append([]string(nil), item.Tickers...)
+	}
+	if item.Entities != nil {
+		item.Entities = append([]string(nil), item.Entities...)
+	}
+	if item.Revisions != nil {
+		revisions := make([]NewsItem, len(item.Revisions))
+		for idx, rev := range item.Revisions {
+			revisions[idx] = cloneNewsItem(rev)
+		}
+		item.Revisions = revisions
+	}
+	return item
+}
+
+// maxReportedRejections bounds the rejections kept in StaticFileStats.
+const maxReportedRejections = 20
+
+// StaticFileSource serves NewsItem documents from a JSON file. Malformed records
+// are skipped and counted in Stats unless Strict is set, in which case a record
+// with an unparseable timestamp fails the fetch.
 type StaticFileSource struct {
-	name string
-	path string
+	name       string
+	path       string
+	Strict     bool
+	Timestamps TimestampOptions
+
+	mu    sync.Mutex
+	stats StaticFileStats
+}
+
+// StaticFileStats reports the outcome of the most recent decode of the file.
+type StaticFileStats struct {
+	Loads    int `json:"loads"`
+	Records  int `json:"records"`
+	Accepted int `json:"accepted"`
+	Rejected int `json:"rejected"`
+	// Rejections holds the first rejected records of the last load.
+	Rejections []Rejection `json:"rejections,omitempty"`
+	// TimestampFormats counts non-canonical published_at formats of the last load.
+	TimestampFormats map[string]int `json:"timestamp_formats,omitempty"`
 }
 
 // NewStaticFileSource returns a new StaticFileSource referencing the given file.
@@ -68,7 +147,8 @@ func NewStaticFileSource(name, path string) (*StaticFileSource, error) {
 // Name returns the source name.
 func (s *StaticFileSource) Name() string { return s.name }
 
-// Fetch reads the JSON file and filters items by timeframe.
+// Fetch reads the JSON file and filters items by timeframe. Every call decodes the
+// file anew, so the returned items are never shared with the source.
 func (s *StaticFileSource) Fetch(ctx context.Context, from, to time.Time) ([]NewsItem, error) {
 	select {
 	case <-ctx.Done():
@@ -81,10 +161,11 @@ func (s *StaticFileSource) Fetch(ctx context.Context, from, to time.Time) ([]New
 		return nil, fmt.Errorf("read static file %s: %w", s.path, err)
 	}
 
-	items, err := decodeNewsItems(raw)
+	items, report, err := decodeNewsItems(raw, s.Strict, s.Timestamps)
 	if err != nil {
 		return nil, fmt.Errorf("decode static file %s: %w", s.path, err)
 	}
+	s.recordLoad(report)
 
 	var filtered []NewsItem
 	for _, item := range items {
@@ -95,3 +176,46 @@ func (s *StaticFileSource) Fetch(ctx context.Context, from, to time.Time) ([]New
 
 	return filtered, nil
 }
+
+// Stats returns the decode statistics of the most recent fetch.
+func (s *StaticFileSource) Stats() StaticFileStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := s.stats
+	stats.Rejections = append([]Rejection(nil), s.stats.Rejections...)
+	if s.stats.TimestampFormats != nil {
+		stats.TimestampFormats = make(map[string]int, len(s.stats.TimestampFormats))
+		for format, count := range s.stats.TimestampFormats {
+			stats.TimestampFormats[format] = count
+		}
+	}
+	return stats
+}
+
+// recordLoad updates the stats and logs the rejection summary whenever it
+// changes, so a bad dump is reported once rather than on every request.
+func (s *StaticFileSource) recordLoad(report DecodeReport) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := s.stats.Loads == 0 || s.stats.Rejected != len(report.Rejected) || s.stats.Records != report.Records
+	s.stats = StaticFileStats{
+		Loads:            s.stats.Loads + 1,
+		Records:          report.Records,
+		Accepted:         report.Accepted,
+		Rejected:         len(report.Rejected),
+		TimestampFormats: report.TimestampFormats,
+	}
+	rejections := report.Rejected
+	if len(rejections) > maxReportedRejections {
+		rejections = rejections[:maxReportedRejections]
+	}
+	s.stats.Rejections = append([]Rejection(nil), rejections...)
+
+	if changed && len(report.Rejected) > 0 {
+		first := report.Rejected[0]
+		log.Printf("StaticFileSource %s: rejected %d of %d records from %s (first: #%d %q: %s)",
+			s.name, len(report.Rejected), report.Records, s.path, first.Index, first.ID, first.Reason)
+	}
+}
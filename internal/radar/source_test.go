@@ -0,0 +1,93 @@
+package radar
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type staticSource struct {
+	name  string
+	items []NewsItem
+}
+
+func (s staticSource) Name() string { return s.name }
+
+func (s staticSource) Fetch(ctx context.Context, from, to time.Time) ([]NewsItem, error) {
+	return s.items, nil
+}
+
+func TestFetchAllResultsDoNotAliasSourceState(t *testing.T) {
+	base := time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)
+	ingest := NewIngestSource("ingest")
+	ingest.Add(NewsItem{ID: "n1", Headline: "First", URL: "https://example.com/1", PublishedAt: base, Tickers: []string{"AAA"}})
+	ingest.Add(NewsItem{ID: "n2", Headline: "Second", URL: "https://example.com/2", PublishedAt: base.Add(time.Hour), Tickers: []string{"BBB"}})
+
+	sources, err := NewSourceRegistry(ingest)
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+
+	items, err := sources.FetchAll(context.Background(), base.Add(-time.Hour), base.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+
+	items[0].Headline = "mutated"
+	items[0].Tickers[0] = "ZZZ"
+	items[0], items[1] = items[1], items[0]
+	if _, err := DefaultClusterer().BuildClusters(context.Background(), items); err != nil {
+		t.Fatalf("cluster: %v", err)
+	}
+
+	again, err := sources.FetchAll(context.Background(), base.Add(-time.Hour), base.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if again[0].ID != "n1" || again[0].Headline != "First" || again[0].Tickers[0] != "AAA" {
+		t.Fatalf("source state changed through FetchAll result: %+v", again[0])
+	}
+}
+
+func TestFetchAllOrdersAcrossSources(t *testing.T) {
+	base := time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)
+	first := staticSource{name: "first", items: []NewsItem{
+		{ID: "a3", PublishedAt: base.Add(3 * time.Hour)},
+		{ID: "a1", PublishedAt: base.Add(time.Hour)},
+		{ID: "b2", PublishedAt: base.Add(2 * time.Hour)},
+		{ID: "a2", PublishedAt: base.Add(2 * time.Hour)},
+	}}
+	second := staticSource{name: "second", items: []NewsItem{
+		{ID: "0", PublishedAt: base.Add(2 * time.Hour)},
+		{ID: "b0", PublishedAt: base},
+		{ID: "b4", PublishedAt: base.Add(4 * time.Hour)},
+	}}
+
+	sources, err := NewSourceRegistry(first, second)
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+
+	items, err := sources.FetchAll(context.Background(), base, base.Add(4*time.Hour))
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+
+	want := []string{"b0", "a1", "a2", "b2", "0", "a3", "b4"}
+	if len(items) != len(want) {
+		t.Fatalf("expected %d items, got %d", len(want), len(items))
+	}
+	for idx, id := range want {
+		if items[idx].ID != id {
+			t.Fatalf("position %d: expected %s, got %s", idx, id, items[idx].ID)
+		}
+	}
+
+	items[0].ID = "mutated"
+	if first.items[0].ID != "a3" || second.items[1].ID != "b0" {
+		t.Fatalf("FetchAll result aliases source slice")
+	}
+}
@@ -0,0 +1,107 @@
+package radar
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Timestamp formats reported by ParseTimestampWith for non-canonical input.
+const (
+	TimestampRFC3339Space  = "rfc3339_space"
+	TimestampRFC3339NoZone = "rfc3339_no_zone"
+	TimestampRFC1123       = "rfc1123"
+	TimestampUnixSeconds   = "unix_seconds"
+	TimestampUnixMillis    = "unix_millis"
+)
+
+// Bare integers are read as epochs only in a plausible range: ten digits of
+// seconds from 2001-09-09 or thirteen digits of milliseconds. Anything else,
+// such as the compact dates 20251003 or 03102025, is rejected.
+const (
+	minUnixSeconds = 1_000_000_000
+	minUnixMillis  = 1_000_000_000_000
+)
+
+// TimestampOptions tunes ParseTimestampWith.
+type TimestampOptions struct {
+	// Strict accepts canonical RFC3339 only.
+	Strict bool
+	// DefaultZone is assumed for timestamps without an offset; nil means UTC.
+	DefaultZone *time.Location
+}
+
+// ParseTimestamp accepts RFC3339 (with optional fractional seconds, a space
+// instead of "T", or no offset, which is read as UTC), RFC1123 (with GMT, UTC
+// or a numeric offset) and 10-digit Unix seconds or 13-digit Unix milliseconds.
+func ParseTimestamp(value string) (time.Time, error) {
+	ts, _, err := ParseTimestampWith(value, TimestampOptions{})
+	return ts, err
+}
+
+// ParseTimestampWith parses value like ParseTimestamp and also returns which
+// non-canonical format matched, or "" for RFC3339, so callers can warn senders.
+func ParseTimestampWith(value string, opts TimestampOptions) (time.Time, string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, "", errors.New("empty timestamp")
+	}
+	if ts, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return ts, "", nil
+	}
+	if opts.Strict {
+		return time.Time{}, "", fmt.Errorf("unsupported timestamp %q: want RFC3339", value)
+	}
+
+	zone := opts.DefaultZone
+	if zone == nil {
+		zone = time.UTC
+	}
+	layouts := []struct {
+		layout string
+		format string
+		zoned  bool
+	}{
+		{"2006-01-02 15:04:05.999999999Z07:00", TimestampRFC3339Space, true},
+		{"2006-01-02T15:04:05.999999999", TimestampRFC3339NoZone, false},
+		{"2006-01-02 15:04:05.999999999", TimestampRFC3339NoZone, false},
+		{time.RFC1123Z, TimestampRFC1123, true},
+		{time.RFC1123, TimestampRFC1123, true},
+	}
+	for _, candidate := range layouts {
+		var (
+			ts  time.Time
+			err error
+		)
+		if candidate.zoned {
+			ts, err = time.Parse(candidate.layout, value)
+		} else {
+			ts, err = time.ParseInLocation(candidate.layout, value, zone)
+		}
+		if err == nil && candidate.layout == time.RFC1123 && !universalZone(ts) {
+			// time.Parse gives unknown abbreviations such as MSK a zero offset.
+			return time.Time{}, "", fmt.Errorf("unsupported timestamp %q: RFC1123 needs GMT, UTC or a numeric offset", value)
+		}
+		if err == nil {
+			return ts, candidate.format, nil
+		}
+	}
+
+	if epoch, err := strconv.ParseInt(value, 10, 64); err == nil {
+		switch {
+		case len(value) == 10 && epoch >= minUnixSeconds:
+			return time.Unix(epoch, 0).UTC(), TimestampUnixSeconds, nil
+		case len(value) == 13 && epoch >= minUnixMillis:
+			return time.UnixMilli(epoch).UTC(), TimestampUnixMillis, nil
+		}
+	}
+	return time.Time{}, "", fmt.Errorf("unsupported timestamp %q: want RFC3339, RFC1123, 10-digit Unix seconds or 13-digit Unix milliseconds", value)
+}
+
+// universalZone reports whether ts was parsed with a GMT or UTC zone name.
+func universalZone(ts time.Time) bool {
+	name, offset := ts.Zone()
+	return offset == 0 && (name == "GMT" || name == "UTC" || name == "UT")
+}
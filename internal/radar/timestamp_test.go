@@ -0,0 +1,71 @@
+package radar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimestampWith(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	at8UTC := time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		input      string
+		opts       TimestampOptions
+		want       time.Time
+		wantFormat string
+		wantErr    bool
+	}{
+		{name: "rfc3339", input: "2025-10-03T08:00:00Z", want: at8UTC},
+		{name: "rfc3339 offset", input: "2025-10-03T11:00:00+03:00", want: at8UTC},
+		{name: "rfc3339 fraction", input: "2025-10-03T08:00:00.5Z", want: at8UTC.Add(500 * time.Millisecond)},
+		{name: "space separated", input: "2025-10-03 11:00:00+03:00", want: at8UTC, wantFormat: TimestampRFC3339Space},
+		{name: "space separated utc", input: "2025-10-03 08:00:00Z", want: at8UTC, wantFormat: TimestampRFC3339Space},
+		{name: "no zone defaults to utc", input: "2025-10-03T08:00:00", want: at8UTC, wantFormat: TimestampRFC3339NoZone},
+		{name: "no zone uses default zone", input: "2025-10-03T11:00:00", opts: TimestampOptions{DefaultZone: moscow}, want: at8UTC, wantFormat: TimestampRFC3339NoZone},
+		{name: "space and no zone", input: "2025-10-03 11:00:00.250", opts: TimestampOptions{DefaultZone: moscow}, want: at8UTC.Add(250 * time.Millisecond), wantFormat: TimestampRFC3339NoZone},
+		{name: "explicit offset ignores default zone", input: "2025-10-03T08:00:00Z", opts: TimestampOptions{DefaultZone: moscow}, want: at8UTC},
+		{name: "rfc1123", input: "Fri, 03 Oct 2025 08:00:00 GMT", want: at8UTC, wantFormat: TimestampRFC1123},
+		{name: "rfc1123z", input: "Fri, 03 Oct 2025 11:00:00 +0300", want: at8UTC, wantFormat: TimestampRFC1123},
+		{name: "rfc1123 utc", input: "Fri, 03 Oct 2025 08:00:00 UTC", want: at8UTC, wantFormat: TimestampRFC1123},
+		{name: "rfc1123 rejects zone abbreviation", input: "Fri, 03 Oct 2025 11:00:00 MSK", wantErr: true},
+		{name: "rfc1123 rejects abbreviation with default zone", input: "Fri, 03 Oct 2025 11:00:00 MSK", opts: TimestampOptions{DefaultZone: moscow}, wantErr: true},
+		{name: "unix seconds", input: "1759478400", want: at8UTC, wantFormat: TimestampUnixSeconds},
+		{name: "unix millis", input: "1759478400123", want: at8UTC.Add(123 * time.Millisecond), wantFormat: TimestampUnixMillis},
+		{name: "strict rfc3339", input: "2025-10-03T08:00:00Z", opts: TimestampOptions{Strict: true}, want: at8UTC},
+		{name: "strict rejects space", input: "2025-10-03 08:00:00Z", opts: TimestampOptions{Strict: true}, wantErr: true},
+		{name: "strict rejects epoch", input: "1759478400", opts: TimestampOptions{Strict: true}, wantErr: true},
+		{name: "day month year", input: "03/10/2025", wantErr: true},
+		{name: "month day year", input: "10/03/2025 08:00", wantErr: true},
+		{name: "date only", input: "2025-10-03", wantErr: true},
+		{name: "fractional epoch", input: "1759478400.5", wantErr: true},
+		{name: "compact date", input: "20251003", wantErr: true},
+		{name: "compact day month year", input: "03102025", wantErr: true},
+		{name: "signed epoch", input: "+1759478400", wantErr: true},
+		{name: "eleven digit epoch", input: "17594784001", wantErr: true},
+		{name: "two digit year", input: "25-10-03 08:00:00", wantErr: true},
+		{name: "empty", input: "  ", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, format, err := ParseTimestampWith(tc.input, tc.opts)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %s (%s)", got, format)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tc.want) {
+				t.Errorf("got %s, want %s", got, tc.want)
+			}
+			if format != tc.wantFormat {
+				t.Errorf("got format %q, want %q", format, tc.wantFormat)
+			}
+		})
+	}
+}
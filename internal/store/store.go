@@ -0,0 +1,306 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultCompactAfter is the minimum number of log records before the log is
+// folded into the snapshot.
+const defaultCompactAfter = 1024
+
+// Store is a small key-value store for operational state such as idempotency
+// keys. Values are JSON-encoded and kept in memory. When a path is configured,
+// every write appends one record to a write-ahead log next to it (path+".log"),
+// and the log is compacted into the snapshot at path once it holds more records
+// than both the live entries and the compaction threshold. A write therefore
+// costs O(1) amortised disk I/O. All entries live in memory and are rewritten on
+// compaction, so the store is sized for up to about 100k entries.
+type Store struct {
+	mu           sync.Mutex
+	path         string
+	now          func() time.Time
+	entries      map[string]entry
+	logRecords   int
+	compactAfter int
+}
+
+type entry struct {
+	Value     json.RawMessage `json:"value"`
+	ExpiresAt time.Time       `json:"expires_at,omitempty"`
+}
+
+// logRecord is one line of the write-ahead log.
+type logRecord struct {
+	Key     string `json:"key"`
+	Entry   *entry `json:"entry,omitempty"`
+	Deleted bool   `json:"deleted,omitempty"`
+}
+
+// Open loads the store from path and replays its log, creating both on first
+// write. An empty path yields an in-memory store.
+func Open(path string, opts ...func(*Store)) (*Store, error) {
+	s := &Store{path: path, now: time.Now, entries: make(map[string]entry), compactAfter: defaultCompactAfter}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("store: read %s: %w", path, err)
+	}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &s.entries); err != nil {
+			return nil, fmt.Errorf("store: decode %s: %w", path, err)
+		}
+	}
+	if err := s.replayLog(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// WithCompactAfter sets the minimum number of log records that triggers a
+// compaction (useful for tests).
+func WithCompactAfter(records int) func(*Store) {
+	return func(s *Store) {
+		if records > 0 {
+			s.compactAfter = records
+		}
+	}
+}
+
+// WithClock overrides the clock used for expiry (useful for tests).
+func WithClock(now func() time.Time) func(*Store) {
+	return func(s *Store) {
+		if now != nil {
+			s.now = now
+		}
+	}
+}
+
+// Get decodes the value stored under key into dst. It reports false when the
+// key is missing or expired.
+func (s *Store) Get(key string, dst any) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok || s.expired(e) {
+		return false, nil
+	}
+	if err := json.Unmarshal(e.Value, dst); err != nil {
+		return false, fmt.Errorf("store: decode %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// Put stores value under key. A positive ttl makes the entry expire.
+func (s *Store) Put(key string, value any, ttl time.Duration) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("store: encode %s: %w", key, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := entry{Value: raw}
+	if ttl > 0 {
+		e.ExpiresAt = s.now().Add(ttl).UTC()
+	}
+	s.entries[key] = e
+	return s.appendLog(logRecord{Key: key, Entry: &e})
+}
+
+// Delete removes key.
+func (s *Store) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.entries[key]; !ok {
+		return nil
+	}
+	delete(s.entries, key)
+	return s.appendLog(logRecord{Key: key, Deleted: true})
+}
+
+// Keys lists the live keys starting with prefix in lexical order.
+func (s *Store) Keys(prefix string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var keys []string
+	for key, e := range s.entries {
+		if strings.HasPrefix(key, prefix) && !s.expired(e) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// PurgeExpired drops expired entries and returns how many were removed.
+func (s *Store) PurgeExpired() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	removed := 0
+	for key, e := range s.entries {
+		if s.expired(e) {
+			delete(s.entries, key)
+			removed++
+		}
+	}
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, s.compact()
+}
+
+// RunJanitor purges expired entries every interval until ctx is done.
+func (s *Store) RunJanitor(ctx context.Context, interval time.Duration, logf func(format string, args ...any)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			removed, err := s.PurgeExpired()
+			if err != nil && logf != nil {
+				logf("store: purge expired: %v", err)
+			} else if removed > 0 && logf != nil {
+				logf("store: purged %d expired entries", removed)
+			}
+		}
+	}
+}
+
+func (s *Store) expired(e entry) bool {
+	return !e.ExpiresAt.IsZero() && !s.now().Before(e.ExpiresAt)
+}
+
+func (s *Store) logPath() string {
+	return s.path + ".log"
+}
+
+// replayLog applies the log on top of the snapshot. A torn last line from a
+// crash mid-append is dropped by compacting right away, so later appends do
+// not land on the same line.
+func (s *Store) replayLog() error {
+	raw, err := os.ReadFile(s.logPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("store: read %s: %w", s.logPath(), err)
+	}
+	lines := bytes.Split(raw, []byte("\n"))
+	for idx, line := range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var rec logRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			if idx == len(lines)-1 {
+				return s.compact()
+			}
+			return fmt.Errorf("store: decode %s line %d: %w", s.logPath(), idx+1, err)
+		}
+		switch {
+		case rec.Deleted:
+			delete(s.entries, rec.Key)
+		case rec.Entry != nil:
+			s.entries[rec.Key] = *rec.Entry
+		}
+		s.logRecords++
+	}
+	return nil
+}
+
+// appendLog persists one change and compacts when the log has outgrown the
+// live entries; callers must hold s.mu.
+func (s *Store) appendLog(rec logRecord) error {
+	if s.path == "" {
+		return nil
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("store: encode %s: %w", rec.Key, err)
+	}
+	f, err := os.OpenFile(s.logPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("store: write %s: %w", s.logPath(), err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("store: write %s: %w", s.logPath(), err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("store: write %s: %w", s.logPath(), err)
+	}
+	s.logRecords++
+	if s.logRecords > s.compactAfter && s.logRecords > len(s.entries) {
+		return s.compact()
+	}
+	return nil
+}
+
+// compact writes a fresh snapshot and then empties the log. Replaying a log
+// left behind by a crash between the two steps yields the same entries.
+// Callers must hold s.mu.
+func (s *Store) compact() error {
+	if s.path == "" {
+		return nil
+	}
+	if err := s.writeSnapshot(); err != nil {
+		return err
+	}
+	if err := os.Remove(s.logPath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("store: truncate %s: %w", s.logPath(), err)
+	}
+	s.logRecords = 0
+	return nil
+}
+
+// writeSnapshot writes the entries atomically; callers must hold s.mu.
+func (s *Store) writeSnapshot() error {
+	if s.path == "" {
+		return nil
+	}
+	raw, err := json.Marshal(s.entries)
+	if err != nil {
+		return fmt.Errorf("store: encode: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("store: write %s: %w", s.path, err)
+	}
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("store: write %s: %w", s.path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("store: write %s: %w", s.path, err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("store: write %s: %w", s.path, err)
+	}
+	return nil
+}
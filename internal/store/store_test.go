@@ -0,0 +1,135 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStorePersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if err := s.Put("a", map[string]int{"n": 1}, 0); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	var got map[string]int
+	found, err := reopened.Get("a", &got)
+	if err != nil || !found {
+		t.Fatalf("expected persisted value, found=%v err=%v", found, err)
+	}
+	if got["n"] != 1 {
+		t.Fatalf("unexpected value: %v", got)
+	}
+}
+
+func TestStoreExpiresAndPurges(t *testing.T) {
+	now := time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)
+	s, err := Open("", WithClock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	if err := s.Put("short", "x", time.Minute); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if err := s.Put("forever", "y", 0); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	var v string
+	if found, _ := s.Get("short", &v); !found {
+		t.Fatalf("entry should be live before ttl")
+	}
+
+	now = now.Add(time.Minute)
+	if found, _ := s.Get("short", &v); found {
+		t.Fatalf("entry should expire after ttl")
+	}
+	if keys := s.Keys(""); len(keys) != 1 || keys[0] != "forever" {
+		t.Fatalf("expected only live keys, got %v", keys)
+	}
+
+	removed, err := s.PurgeExpired()
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if removed != 1 {
+		t.Fatalf("expected 1 purged entry, got %d", removed)
+	}
+}
+
+func TestStoreCompactsLogAndReplaysAfterReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s, err := Open(path, WithCompactAfter(8))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	// Repeated writes to a few live keys must not grow the log without bound.
+	for i := 0; i < 100; i++ {
+		if err := s.Put(fmt.Sprintf("k%d", i%4), i, 0); err != nil {
+			t.Fatalf("put: %v", err)
+		}
+		if s.logRecords > 8 {
+			t.Fatalf("log grew to %d records past the compaction threshold", s.logRecords)
+		}
+	}
+	if err := s.Delete("k0"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if keys := reopened.Keys(""); len(keys) != 3 {
+		t.Fatalf("expected 3 keys after replay, got %v", keys)
+	}
+	var got int
+	if found, err := reopened.Get("k3", &got); err != nil || !found || got != 99 {
+		t.Fatalf("expected the last write to win, got %d found=%v err=%v", got, found, err)
+	}
+}
+
+func TestStoreIgnoresTornLogTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if err := s.Put("a", 1, 0); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	f, err := os.OpenFile(path+".log", os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		t.Fatalf("open log: %v", err)
+	}
+	_, _ = f.WriteString(`{"key":"b","entry":{"val`)
+	f.Close()
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("reopen with torn tail: %v", err)
+	}
+	if err := reopened.Put("c", 3, 0); err != nil {
+		t.Fatalf("put after recovery: %v", err)
+	}
+	again, err := Open(path)
+	if err != nil {
+		t.Fatalf("reopen after recovery: %v", err)
+	}
+	if keys := again.Keys(""); len(keys) != 2 || keys[0] != "a" || keys[1] != "c" {
+		t.Fatalf("expected a and c, got %v", keys)
+	}
+}
@@ -0,0 +1,74 @@
+package transporthttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"finamhackbackend/internal/radar"
+)
+
+type debugCluster struct {
+	ID         string    `json:"id"`
+	PrimaryID  string    `json:"primary_id"`
+	NewsIDs    []string  `json:"news_ids"`
+	Confidence float64   `json:"confidence"`
+	Noise      bool      `json:"noise"`
+	Annotated  bool      `json:"annotated"`
+	StartTime  time.Time `json:"start_time"`
+	EndTime    time.Time `json:"end_time"`
+}
+
+// handleDebugClusters exposes the raw clustering output for the same window parameters as /radar.
+func (s *Server) handleDebugClusters(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	params := s.parseParams(r)
+	clusters, err := s.pipeline.Clusters(ctx, radar.QueryParams{
+		From:     params.from,
+		To:       params.to,
+		Language: params.language,
+	})
+	if err != nil && !errors.Is(err, radar.ErrWindowEmpty) {
+		s.writePipelineError(w, err)
+		return
+	}
+
+	out := make([]debugCluster, 0, len(clusters))
+	for _, cluster := range clusters {
+		ids := make([]string, 0, len(cluster.Items))
+		for _, item := range cluster.Items {
+			ids = append(ids, item.ID)
+		}
+		out = append(out, debugCluster{
+			ID:         cluster.ID,
+			PrimaryID:  cluster.Primary.ID,
+			NewsIDs:    ids,
+			Confidence: cluster.Confidence,
+			Noise:      cluster.Noise,
+			Annotated:  cluster.Annotations != nil,
+			StartTime:  cluster.StartTime,
+			EndTime:    cluster.EndTime,
+		})
+	}
+
+	response := map[string]any{
+		"as_of":    s.now(),
+		"from":     params.from,
+		"to":       params.to,
+		"clusters": out,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,132 @@
+package transporthttp
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"finamhackbackend/internal/editorial"
+	"finamhackbackend/internal/radar"
+)
+
+// radarEvent is an event as served by /radar, with its editorial state merged in.
+type radarEvent struct {
+	radar.Event
+	Editorial *editorial.Record `json:"editorial,omitempty"`
+}
+
+// EnableEditorial exposes the editorial workflow endpoints and merges editorial
+// state into /radar responses.
+func (s *Server) EnableEditorial(board *editorial.Board) {
+	s.editorial = board
+}
+
+// mergeEditorial attaches editorial state to events and, unless includeRejected
+// is set, drops rejected events before applying limit. Only events up to the
+// limit are looked up, so the cost follows the response, not editorial history.
+func (s *Server) mergeEditorial(events []radar.Event, includeRejected bool, limit int) []radarEvent {
+	merged := make([]radarEvent, 0, len(events))
+	for _, event := range events {
+		if limit > 0 && len(merged) == limit {
+			break
+		}
+		out := radarEvent{Event: event}
+		if s.editorial != nil {
+			rec, ok, err := s.editorial.Get(event.ID)
+			if err != nil {
+				log.Printf("editorial: load %s: %v", event.ID, err)
+			} else if ok {
+				if rec.State == editorial.StateRejected && !includeRejected {
+					continue
+				}
+				out.Editorial = &rec
+			}
+		}
+		merged = append(merged, out)
+	}
+	return merged
+}
+
+func (s *Server) handleEventState(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/events/"), "/state")
+	if !ok || eventID == "" || strings.Contains(eventID, "/") {
+		s.writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		rec, found, err := s.editorial.Get(eventID)
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, "failed to load editorial state")
+			return
+		}
+		if !found {
+			rec = editorial.Record{EventID: eventID, State: editorial.StateNew}
+		}
+		writeJSON(w, http.StatusOK, rec)
+	case http.MethodPut:
+		var payload struct {
+			State  string `json:"state"`
+			Note   string `json:"note"`
+			Editor string `json:"editor"`
+		}
+		decoder := json.NewDecoder(io.LimitReader(r.Body, maxIngestBody))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&payload); err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid JSON payload")
+			return
+		}
+		state, err := editorial.ParseState(payload.State)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, "state must be one of new, in_review, approved, published, rejected")
+			return
+		}
+		rec, err := s.editorial.Set(eventID, state, payload.Note, payload.Editor)
+		switch {
+		case errors.Is(err, editorial.ErrInvalidTransition):
+			s.writeError(w, http.StatusConflict, err.Error())
+		case err != nil:
+			s.writeError(w, http.StatusInternalServerError, "failed to store editorial state")
+		default:
+			writeJSON(w, http.StatusOK, rec)
+		}
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) handleEventStates(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var state editorial.State
+	if raw := r.URL.Query().Get("state"); raw != "" {
+		parsed, err := editorial.ParseState(raw)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, "state must be one of new, in_review, approved, published, rejected")
+			return
+		}
+		state = parsed
+	}
+
+	records, err := s.editorial.List(state)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to list editorial states")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"states": records})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,156 @@
+package transporthttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"finamhackbackend/internal/config"
+	"finamhackbackend/internal/editorial"
+	"finamhackbackend/internal/radar"
+	"finamhackbackend/internal/store"
+)
+
+type editorialEvent struct {
+	ID        string            `json:"id"`
+	Tickers   []string          `json:"tickers"`
+	Editorial *editorial.Record `json:"editorial"`
+}
+
+func fetchRadarEvents(t *testing.T, handler http.Handler, query string) []editorialEvent {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/radar?from=2025-10-03T00:00:00Z&to=2025-10-04T00:00:00Z"+query, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("radar: expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var payload struct {
+		Events []editorialEvent `json:"events"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode radar: %v", err)
+	}
+	return payload.Events
+}
+
+func putState(handler http.Handler, eventID, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/events/"+eventID+"/state", strings.NewReader(body)))
+	return rec
+}
+
+func TestEditorialStateMergesIntoRadar(t *testing.T) {
+	ingest := radar.NewIngestSource("test-ingest")
+	base := time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)
+	ingest.Add(radar.NewsItem{ID: "n1", Headline: "Sber raises outlook", Source: "reuters", PublishedAt: base, Tickers: []string{"SBER"}})
+	ingest.Add(radar.NewsItem{ID: "n2", Headline: "Gazprom cuts exports", Source: "bloomberg", PublishedAt: base.Add(time.Hour), Tickers: []string{"GAZP"}})
+	sources, err := radar.NewSourceRegistry(ingest)
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+	pipeline, err := radar.NewPipeline(sources, radar.DefaultClusterer(), radar.DefaultScorer())
+	if err != nil {
+		t.Fatalf("pipeline: %v", err)
+	}
+	st, err := store.Open("")
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	srv := NewServer(pipeline, config.Config{DefaultWindow: 24 * time.Hour, TopK: 1}, ingest)
+	srv.EnableEditorial(editorial.NewBoard(st))
+	handler := srv.Routes()
+
+	events := fetchRadarEvents(t, handler, "&limit=2")
+	if len(events) != 2 || events[0].ID == "" || events[0].Editorial != nil {
+		t.Fatalf("expected two events without editorial state, got %+v", events)
+	}
+	rejected, kept := events[0], events[1]
+
+	if rec := putState(handler, kept.ID, `{"state":"published"}`); rec.Code != http.StatusConflict {
+		t.Fatalf("publishing a new event should conflict, got %d", rec.Code)
+	}
+	if rec := putState(handler, kept.ID, `{"state":"closed"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("unknown state should be rejected, got %d", rec.Code)
+	}
+	if rec := putState(handler, kept.ID, `{"state":"in_review","note":"verify figures","editor":"anna"}`); rec.Code != http.StatusOK {
+		t.Fatalf("set in_review: %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := putState(handler, rejected.ID, `{"state":"rejected","editor":"boris"}`); rec.Code != http.StatusOK {
+		t.Fatalf("set rejected: %d %s", rec.Code, rec.Body.String())
+	}
+
+	// the default limit is 1: the rejected top event is hidden and the next one fills its slot
+	events = fetchRadarEvents(t, handler, "")
+	if len(events) != 1 || events[0].ID != kept.ID {
+		t.Fatalf("expected only the reviewed event, got %+v", events)
+	}
+	if events[0].Editorial == nil || events[0].Editorial.State != editorial.StateInReview || events[0].Editorial.Note != "verify figures" {
+		t.Fatalf("expected merged editorial state, got %+v", events[0].Editorial)
+	}
+
+	events = fetchRadarEvents(t, handler, "&limit=2&include_rejected=true")
+	if len(events) != 2 || events[0].Editorial == nil || events[0].Editorial.State != editorial.StateRejected {
+		t.Fatalf("expected rejected event when requested, got %+v", events)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events/states?state=in_review", nil))
+	var listed struct {
+		States []editorial.Record `json:"states"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &listed); err != nil {
+		t.Fatalf("decode states: %v", err)
+	}
+	if len(listed.States) != 1 || listed.States[0].EventID != kept.ID {
+		t.Fatalf("expected one in_review record, got %+v", listed.States)
+	}
+}
+
+func TestRadarHidesRejectedBeforeApplyingLimit(t *testing.T) {
+	ingest := radar.NewIngestSource("test-ingest")
+	base := time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)
+	for idx, ticker := range []string{"SBER", "GAZP", "LKOH", "NVTK"} {
+		ingest.Add(radar.NewsItem{ID: ticker, Headline: ticker + " update", Source: "reuters", PublishedAt: base.Add(time.Duration(idx) * time.Hour), Tickers: []string{ticker}})
+	}
+	sources, err := radar.NewSourceRegistry(ingest)
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+	pipeline, err := radar.NewPipeline(sources, radar.DefaultClusterer(), radar.DefaultScorer())
+	if err != nil {
+		t.Fatalf("pipeline: %v", err)
+	}
+	st, err := store.Open("")
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	board := editorial.NewBoard(st)
+	srv := NewServer(pipeline, config.Config{DefaultWindow: 24 * time.Hour, TopK: 2}, ingest)
+	srv.EnableEditorial(board)
+	handler := srv.Routes()
+
+	all := fetchRadarEvents(t, handler, "&limit=4")
+	if len(all) != 4 {
+		t.Fatalf("expected 4 events, got %d", len(all))
+	}
+	// old rejections outside the window must not change the result
+	for idx := 0; idx < 20; idx++ {
+		if _, err := board.Set(fmt.Sprintf("evt_old_%d", idx), editorial.StateRejected, "", ""); err != nil {
+			t.Fatalf("reject old: %v", err)
+		}
+	}
+	for _, event := range all[:2] {
+		if _, err := board.Set(event.ID, editorial.StateRejected, "", ""); err != nil {
+			t.Fatalf("reject: %v", err)
+		}
+	}
+
+	events := fetchRadarEvents(t, handler, "")
+	if len(events) != 2 || events[0].ID != all[2].ID || events[1].ID != all[3].ID {
+		t.Fatalf("expected the next two events to fill the limit, got %+v", events)
+	}
+}
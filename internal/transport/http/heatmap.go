@@ -0,0 +1,100 @@
+package transporthttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"finamhackbackend/internal/radar"
+)
+
+// maxHeatmapBuckets bounds the matrix width a single request may ask for.
+const maxHeatmapBuckets = 24 * 14
+
+func (s *Server) handleHeatmap(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	values := r.URL.Query()
+
+	window := 24 * time.Hour
+	if v := values.Get("window_hours"); v != "" {
+		hrs, err := strconv.Atoi(v)
+		if err != nil || hrs <= 0 {
+			s.writeError(w, http.StatusBadRequest, "window_hours must be a positive integer")
+			return
+		}
+		window = time.Duration(hrs) * time.Hour
+	}
+
+	bucket := time.Hour
+	if v := values.Get("bucket"); v != "" {
+		parsed, err := time.ParseDuration(v)
+		if err != nil || parsed < time.Hour || parsed%time.Hour != 0 {
+			s.writeError(w, http.StatusBadRequest, "bucket must be a whole number of hours, e.g. 1h")
+			return
+		}
+		bucket = parsed
+	}
+
+	top := 20
+	if v := values.Get("top"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed <= 0 {
+			s.writeError(w, http.StatusBadRequest, "top must be a positive integer")
+			return
+		}
+		top = parsed
+	}
+
+	to := s.now()
+	if v := values.Get("to"); v != "" {
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, "to must be RFC3339")
+			return
+		}
+		to = parsed
+	}
+
+	start, count := radar.HeatmapBounds(to, window, bucket)
+	if count > maxHeatmapBuckets {
+		s.writeError(w, http.StatusBadRequest, "too many buckets; widen bucket or shorten window_hours")
+		return
+	}
+	end := start.Add(time.Duration(count) * bucket)
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	// A single run over the whole window; events are spread over buckets by their source timestamps.
+	events, err := s.pipeline.Run(ctx, radar.QueryParams{
+		From:     start,
+		To:       end,
+		Limit:    math.MaxInt32,
+		Language: values.Get("lang"),
+	})
+	if err != nil && !errors.Is(err, radar.ErrWindowEmpty) {
+		s.writePipelineError(w, err)
+		return
+	}
+
+	response := map[string]any{
+		"as_of":   s.now(),
+		"from":    start,
+		"to":      end,
+		"bucket":  bucket.String(),
+		"heatmap": radar.BuildHeatmap(events, start, bucket, count, top),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,121 @@
+package transporthttp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"finamhackbackend/internal/store"
+)
+
+const idempotencyKeyPrefix = "idempotency:"
+
+// idempotentResponse is the stored outcome of an ingest request.
+type idempotentResponse struct {
+	Status      int    `json:"status"`
+	Body        []byte `json:"body"`
+	PayloadHash string `json:"payload_hash"`
+}
+
+// EnableIdempotency makes POST /news honour the Idempotency-Key header: a retry
+// with the same key and payload replays the original response instead of
+// storing the item again. Keys are remembered for ttl.
+func (s *Server) EnableIdempotency(st *store.Store, ttl time.Duration) {
+	s.idempotency = st
+	s.idempotencyTTL = ttl
+}
+
+// idempotencyKey scopes the client-provided key to the caller's credentials so
+// tenants cannot collide. It returns "" when the request carries no key.
+func idempotencyKey(r *http.Request) string {
+	key := strings.TrimSpace(r.Header.Get("Idempotency-Key"))
+	if key == "" {
+		return ""
+	}
+	tenant := "anonymous"
+	if credential := r.Header.Get("Authorization"); credential != "" {
+		tenant = hashHex(credential)[:16]
+	} else if credential := r.Header.Get("X-API-Key"); credential != "" {
+		tenant = hashHex(credential)[:16]
+	}
+	return idempotencyKeyPrefix + tenant + ":" + key
+}
+
+// idempotencySlot serialises requests sharing one key. done holds the response
+// of a finished request until it is in the store, so retries never miss it while
+// the store is being written.
+type idempotencySlot struct {
+	mu   sync.Mutex
+	done *idempotentResponse
+	refs int
+}
+
+// acquireIdempotency locks the slot for key, creating it on first use.
+func (s *Server) acquireIdempotency(key string) *idempotencySlot {
+	s.idempotencyMu.Lock()
+	if s.idempotencySlots == nil {
+		s.idempotencySlots = make(map[string]*idempotencySlot)
+	}
+	slot, ok := s.idempotencySlots[key]
+	if !ok {
+		slot = &idempotencySlot{}
+		s.idempotencySlots[key] = slot
+	}
+	slot.refs++
+	s.idempotencyMu.Unlock()
+
+	slot.mu.Lock()
+	return slot
+}
+
+// releaseIdempotency drops a reference taken by acquireIdempotency; the slot
+// must already be unlocked.
+func (s *Server) releaseIdempotency(key string, slot *idempotencySlot) {
+	s.idempotencyMu.Lock()
+	defer s.idempotencyMu.Unlock()
+	slot.refs--
+	if slot.refs == 0 {
+		delete(s.idempotencySlots, key)
+	}
+}
+
+// lookupIdempotency returns the response recorded for key, checking the slot
+// before the store; callers must hold slot.mu.
+func (s *Server) lookupIdempotency(key string, slot *idempotencySlot) (idempotentResponse, bool) {
+	if slot.done != nil {
+		return *slot.done, true
+	}
+	var previous idempotentResponse
+	found, err := s.idempotency.Get(key, &previous)
+	if err != nil {
+		log.Printf("idempotency lookup failed: %v", err)
+	}
+	return previous, found
+}
+
+// payloadHash fingerprints the decoded payload so retries that differ only in
+// whitespace or field order are recognised as the same request.
+func payloadHash(payload any) string {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return ""
+	}
+	return hashHex(string(raw))
+}
+
+func hashHex(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+func writeStoredResponse(w http.ResponseWriter, resp idempotentResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(resp.Status)
+	_, _ = w.Write(resp.Body)
+}
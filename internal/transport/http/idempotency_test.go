@@ -0,0 +1,219 @@
+package transporthttp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"finamhackbackend/internal/config"
+	"finamhackbackend/internal/radar"
+	"finamhackbackend/internal/store"
+)
+
+func newIdempotentServer(t *testing.T, st *store.Store) (*Server, *radar.IngestSource) {
+	t.Helper()
+	ingest := radar.NewIngestSource("test-ingest")
+	sources, err := radar.NewSourceRegistry(ingest)
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+	pipeline, err := radar.NewPipeline(sources, radar.DefaultClusterer(), radar.DefaultScorer())
+	if err != nil {
+		t.Fatalf("pipeline: %v", err)
+	}
+	srv := NewServer(pipeline, config.Config{DefaultWindow: 24 * time.Hour, TopK: 2}, ingest)
+	srv.EnableIdempotency(st, time.Hour)
+	return srv, ingest
+}
+
+func postNews(srv *Server, body, key, auth string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/news", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	rec := httptest.NewRecorder()
+	srv.handleIngest(rec, req)
+	return rec
+}
+
+func storedCount(t *testing.T, ingest *radar.IngestSource) int {
+	t.Helper()
+	items, err := ingest.Fetch(context.Background(), time.Time{}, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	return len(items)
+}
+
+const idempotentBody = `{"headline":"Earnings beat","url":"https://example.com/a","published_at":"2025-10-03T08:00:00Z"}`
+
+func TestIngestIdempotencySuppressesDuplicates(t *testing.T) {
+	st, _ := store.Open("")
+	srv, ingest := newIdempotentServer(t, st)
+
+	first := postNews(srv, idempotentBody, "k1", "")
+	second := postNews(srv, idempotentBody, "k1", "")
+
+	if first.Code != http.StatusAccepted || second.Code != http.StatusAccepted {
+		t.Fatalf("expected 202 twice, got %d and %d", first.Code, second.Code)
+	}
+	if first.Body.String() != second.Body.String() {
+		t.Fatalf("retry should replay the original body:\n%s\n%s", first.Body, second.Body)
+	}
+	if second.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("replayed response should be marked")
+	}
+	if n := storedCount(t, ingest); n != 1 {
+		t.Fatalf("expected 1 stored item, got %d", n)
+	}
+
+	if rec := postNews(srv, idempotentBody, "", ""); rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202 without key, got %d", rec.Code)
+	}
+	if n := storedCount(t, ingest); n != 2 {
+		t.Fatalf("requests without a key should not be deduplicated, got %d items", n)
+	}
+}
+
+func TestIngestIdempotencyKeysExpire(t *testing.T) {
+	now := time.Now()
+	st, _ := store.Open("", store.WithClock(func() time.Time { return now }))
+	srv, ingest := newIdempotentServer(t, st)
+
+	postNews(srv, idempotentBody, "k1", "")
+	now = now.Add(2 * time.Hour)
+	postNews(srv, idempotentBody, "k1", "")
+
+	if n := storedCount(t, ingest); n != 2 {
+		t.Fatalf("expired key should allow a new item, got %d items", n)
+	}
+}
+
+func TestIngestIdempotencyIsScopedPerTenant(t *testing.T) {
+	st, _ := store.Open("")
+	srv, ingest := newIdempotentServer(t, st)
+
+	postNews(srv, idempotentBody, "shared", "Bearer tenant-a")
+	postNews(srv, idempotentBody, "shared", "Bearer tenant-b")
+	postNews(srv, idempotentBody, "shared", "Bearer tenant-a")
+
+	if n := storedCount(t, ingest); n != 2 {
+		t.Fatalf("expected one item per tenant, got %d", n)
+	}
+}
+
+func TestIngestIdempotencyWithExplicitID(t *testing.T) {
+	st, _ := store.Open("")
+	srv, ingest := newIdempotentServer(t, st)
+
+	original := `{"id":"article-1","headline":"Original","url":"https://example.com/a","published_at":"2025-10-03T08:00:00Z"}`
+	updated := `{"id":"article-1","headline":"Corrected","url":"https://example.com/a","published_at":"2025-10-03T08:00:00Z"}`
+
+	postNews(srv, original, "k1", "")
+	if rec := postNews(srv, updated, "k1", ""); rec.Code != http.StatusConflict {
+		t.Fatalf("reusing a key for a different payload should conflict, got %d", rec.Code)
+	}
+
+	if rec := postNews(srv, updated, "k2", ""); rec.Code != http.StatusAccepted {
+		t.Fatalf("a new key should allow the explicit-ID overwrite, got %d", rec.Code)
+	}
+
+	items, err := ingest.Fetch(context.Background(), time.Time{}, time.Now())
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if len(items) != 1 || items[0].Headline != "Corrected" {
+		t.Fatalf("expected the item to be overwritten once, got %+v", items)
+	}
+}
+
+func TestIngestIdempotencySurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	st, err := store.Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	srv, _ := newIdempotentServer(t, st)
+	first := postNews(srv, idempotentBody, "k1", "")
+
+	reopened, err := store.Open(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	restarted, ingest := newIdempotentServer(t, reopened)
+	second := postNews(restarted, idempotentBody, "k1", "")
+
+	if second.Body.String() != first.Body.String() {
+		t.Fatalf("retry after restart should replay the original response")
+	}
+	if n := storedCount(t, ingest); n != 0 {
+		t.Fatalf("retry after restart should not store the item again, got %d", n)
+	}
+}
+
+func TestIngestIdempotencyIgnoresPayloadFormatting(t *testing.T) {
+	st, _ := store.Open("")
+	srv, ingest := newIdempotentServer(t, st)
+
+	reordered := `{
+		"published_at": "2025-10-03T08:00:00Z",
+		"url": "https://example.com/a",
+		"headline": "Earnings beat"
+	}`
+	postNews(srv, idempotentBody, "k1", "")
+	if rec := postNews(srv, reordered, "k1", ""); rec.Code != http.StatusAccepted || rec.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatalf("reformatted retry should replay, got %d", rec.Code)
+	}
+	if n := storedCount(t, ingest); n != 1 {
+		t.Fatalf("expected 1 stored item, got %d", n)
+	}
+}
+
+func TestIngestIdempotencyConcurrentRetries(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	srv, ingest := newIdempotentServer(t, st)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			postNews(srv, idempotentBody, "same", "")
+			postNews(srv, idempotentBody, fmt.Sprintf("other-%d", i), "")
+		}(i)
+	}
+	wg.Wait()
+
+	if n := storedCount(t, ingest); n != 9 {
+		t.Fatalf("expected one item for the shared key and one per other key, got %d", n)
+	}
+	if len(srv.idempotencySlots) != 0 {
+		t.Fatalf("expected idempotency slots to be released, got %d", len(srv.idempotencySlots))
+	}
+}
+
+func TestIngestRejectsOversizedPayload(t *testing.T) {
+	st, _ := store.Open("")
+	srv, ingest := newIdempotentServer(t, st)
+
+	body := `{"headline":"Earnings beat","url":"https://example.com/a","body":"` + strings.Repeat("x", maxIngestBody) + `"}`
+	if rec := postNews(srv, body, "", ""); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413, got %d", rec.Code)
+	}
+	if n := storedCount(t, ingest); n != 0 {
+		t.Fatalf("oversized payload should not be stored, got %d", n)
+	}
+}
@@ -0,0 +1,77 @@
+package transporthttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"finamhackbackend/internal/radar"
+)
+
+// EnableReplay switches the server to the replay clock and exposes the replay admin endpoints.
+func (s *Server) EnableReplay(replay *radar.ReplayController) {
+	s.replay = replay
+}
+
+// now returns the reference time for default windows: virtual time during replay, wall time otherwise.
+func (s *Server) now() time.Time {
+	if s.replay != nil {
+		return s.replay.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func (s *Server) handleReplayStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	s.writeReplayStatus(w)
+}
+
+func (s *Server) handleReplayControl(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	values := r.URL.Query()
+	switch strings.TrimPrefix(r.URL.Path, "/admin/replay/") {
+	case "start":
+		s.replay.Start()
+	case "pause":
+		s.replay.Pause()
+	case "seek":
+		ts, err := time.Parse(time.RFC3339, values.Get("to"))
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, "to must be RFC3339")
+			return
+		}
+		s.replay.Seek(ts)
+	case "speed":
+		factor, err := strconv.ParseFloat(values.Get("factor"), 64)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, "factor must be a number")
+			return
+		}
+		if err := s.replay.SetSpeed(factor); err != nil {
+			s.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	default:
+		s.writeError(w, http.StatusNotFound, "unknown replay command")
+		return
+	}
+
+	s.writeReplayStatus(w)
+}
+
+func (s *Server) writeReplayStatus(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(s.replay.Status())
+}
@@ -0,0 +1,97 @@
+package transporthttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"finamhackbackend/internal/config"
+	"finamhackbackend/internal/radar"
+)
+
+func TestReplayAdminDrivesRadarState(t *testing.T) {
+	base := time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)
+	items := []radar.NewsItem{
+		{ID: "n1", Headline: "NordTech issues profit warning", URL: "https://example.com/1", Source: "Reuters", PublishedAt: base, Tickers: []string{"NTCH"}},
+		{ID: "n2", Headline: "Central bank signals pause", URL: "https://example.com/2", Source: "Bloomberg", PublishedAt: base.Add(2 * time.Hour), Tickers: []string{"RUB"}},
+		{ID: "n3", Headline: "NordTech supplier halts lines", URL: "https://example.com/3", Source: "Bloomberg", PublishedAt: base.Add(4 * time.Hour), Tickers: []string{"NTCH"}},
+	}
+
+	clock := radar.NewReplayController(base, base.Add(4*time.Hour), 60, nil)
+	source, err := radar.NewReplaySource("replay", items, clock)
+	if err != nil {
+		t.Fatalf("replay source: %v", err)
+	}
+	sources, err := radar.NewSourceRegistry(source)
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+	pipeline, err := radar.NewPipeline(sources, radar.DefaultClusterer(), radar.DefaultScorer())
+	if err != nil {
+		t.Fatalf("pipeline: %v", err)
+	}
+
+	srv := NewServer(pipeline, config.Config{DefaultWindow: 24 * time.Hour, TopK: 5}, nil)
+	srv.EnableReplay(clock)
+	handler := srv.Routes()
+
+	seen := make(map[string]struct{})
+	for _, ts := range []time.Time{base, base.Add(2 * time.Hour), base.Add(4 * time.Hour)} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/replay/seek?to="+ts.Format(time.RFC3339), nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("seek: expected status 200, got %d", rec.Code)
+		}
+
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/radar", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("radar: expected status 200, got %d", rec.Code)
+		}
+
+		var payload struct {
+			AsOf   time.Time     `json:"as_of"`
+			Events []radar.Event `json:"events"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if !payload.AsOf.Equal(ts) {
+			t.Errorf("expected as_of %s, got %s", ts, payload.AsOf)
+		}
+
+		var state string
+		for _, event := range payload.Events {
+			state += fmt.Sprintf("%s|%d;", event.Headline, len(event.Sources))
+		}
+		seen[state] = struct{}{}
+	}
+
+	if len(seen) != 3 {
+		t.Fatalf("expected 3 distinct radar states during replay, got %d", len(seen))
+	}
+}
+
+func TestReplayAdminRejectsInvalidSpeed(t *testing.T) {
+	clock := radar.NewReplayController(time.Now(), time.Time{}, 1, nil)
+	sources, err := radar.NewSourceRegistry(radar.NewIngestSource("ingest"))
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+	pipeline, err := radar.NewPipeline(sources, radar.DefaultClusterer(), radar.DefaultScorer())
+	if err != nil {
+		t.Fatalf("pipeline: %v", err)
+	}
+
+	srv := NewServer(pipeline, config.Config{DefaultWindow: 24 * time.Hour, TopK: 5}, nil)
+	srv.EnableReplay(clock)
+
+	rec := httptest.NewRecorder()
+	srv.Routes().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/replay/speed?factor=-2", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", rec.Code)
+	}
+}
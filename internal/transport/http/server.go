@@ -1,22 +1,44 @@
 package transporthttp
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
 	"net/http"
 	"strconv"
-	"strings"
+	"sync"
 	"time"
 
 	"finamhackbackend/internal/config"
+	"finamhackbackend/internal/editorial"
 	"finamhackbackend/internal/radar"
+	"finamhackbackend/internal/store"
 )
 
+// maxIngestBody bounds the size of a single POST /news payload.
+const maxIngestBody = 1 << 20
+
 type Server struct {
 	pipeline      *radar.Pipeline
 	defaultWindow time.Duration
 	defaultLimit  int
 	ingest        *radar.IngestSource
+	timestamps    radar.TimestampOptions
+	maxAge        time.Duration
+	rejectStale   bool
+	replay        *radar.ReplayController
+	batchTuner    *radar.BatchTuner
+	editorial     *editorial.Board
+
+	idempotency    *store.Store
+	idempotencyTTL time.Duration
+	// idempotencyMu guards idempotencySlots only; requests lock their own slot.
+	idempotencyMu    sync.Mutex
+	idempotencySlots map[string]*idempotencySlot
 }
 
 func NewServer(pipeline *radar.Pipeline, cfg config.Config, ingest *radar.IngestSource) *Server {
@@ -25,6 +47,9 @@ func NewServer(pipeline *radar.Pipeline, cfg config.Config, ingest *radar.Ingest
 		defaultWindow: cfg.DefaultWindow,
 		defaultLimit:  cfg.TopK,
 		ingest:        ingest,
+		timestamps:    radar.TimestampOptions{Strict: cfg.StrictTimestamps, DefaultZone: cfg.TimestampZone},
+		maxAge:        cfg.IngestMaxAge,
+		rejectStale:   cfg.IngestStaleMode != "flag",
 	}
 }
 
@@ -32,7 +57,23 @@ func (s *Server) Routes() http.Handler {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/healthz", s.health)
 	mux.HandleFunc("/radar", s.handleRadar)
+	mux.HandleFunc("/radar/heatmap", s.handleHeatmap)
+	mux.HandleFunc("/debug/clusters", s.handleDebugClusters)
 	mux.HandleFunc("/news", s.handleIngest)
+	if s.pipeline.Shadow != nil {
+		mux.HandleFunc("/admin/shadow", s.handleShadowSummary)
+	}
+	if s.editorial != nil {
+		mux.HandleFunc("/events/states", s.handleEventStates)
+		mux.HandleFunc("/events/", s.handleEventState)
+	}
+	if s.batchTuner != nil {
+		mux.HandleFunc("/admin/llm", s.handleBatchStats)
+	}
+	if s.replay != nil {
+		mux.HandleFunc("/admin/replay", s.handleReplayStatus)
+		mux.HandleFunc("/admin/replay/", s.handleReplayControl)
+	}
 	mux.HandleFunc("/swagger/openapi.yaml", serveSwaggerYAML)
 	mux.HandleFunc("/swagger", serveSwaggerUI)
 	mux.HandleFunc("/swagger/", serveSwaggerUI)
@@ -50,24 +91,42 @@ func (s *Server) handleRadar(w http.ResponseWriter, r *http.Request) {
 	defer cancel()
 
 	params := s.parseParams(r)
+	if r.URL.Query().Get("as_of") != "" && params.asOf.IsZero() {
+		s.writeError(w, http.StatusBadRequest, "as_of must be an RFC3339 timestamp not in the future")
+		return
+	}
 	paramsCtx := radar.QueryParams{
 		From:     params.from,
 		To:       params.to,
 		Limit:    params.limit,
 		Language: params.language,
+		AsOf:     params.asOf,
+	}
+	includeRejected := r.URL.Query().Get("include_rejected") == "true"
+	if s.editorial != nil && !includeRejected {
+		// rejected events are hidden below, so rank the whole window and let
+		// mergeEditorial apply the limit
+		paramsCtx.Unlimited = true
 	}
 
-	events, err := s.pipeline.Run(ctx, paramsCtx)
-	if err != nil {
-		s.writeError(w, http.StatusInternalServerError, err.Error())
+	events, meta, err := s.pipeline.RunWithMeta(ctx, paramsCtx)
+	if err != nil && !errors.Is(err, radar.ErrWindowEmpty) {
+		s.writePipelineError(w, err)
 		return
 	}
 
 	response := map[string]any{
-		"as_of":  time.Now().UTC(),
+		"as_of":  s.now(),
 		"from":   paramsCtx.From,
 		"to":     paramsCtx.To,
-		"events": events,
+		"events": s.mergeEditorial(events, includeRejected, params.limit),
+	}
+	if !params.asOf.IsZero() {
+		response["meta"] = map[string]any{
+			"historical":   true,
+			"as_of":        params.asOf,
+			"degradations": meta.Degradations,
+		}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -77,6 +136,35 @@ func (s *Server) handleRadar(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (s *Server) handleShadowSummary(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(s.pipeline.Shadow.Summary())
+}
+
+// EnableBatchStats exposes the LLM batch tuner statistics on GET /admin/llm.
+func (s *Server) EnableBatchStats(tuner *radar.BatchTuner) {
+	s.batchTuner = tuner
+}
+
+func (s *Server) handleBatchStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(map[string]any{"models": s.batchTuner.Stats()})
+}
+
 func (s *Server) handleIngest(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		w.Header().Set("Allow", http.MethodPost)
@@ -105,26 +193,68 @@ func (s *Server) handleIngest(w http.ResponseWriter, r *http.Request) {
 		ImportanceTag string   `json:"importance_tag"`
 	}
 
-	decoder := json.NewDecoder(r.Body)
+	raw, err := io.ReadAll(io.LimitReader(r.Body, maxIngestBody+1))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid payload")
+		return
+	}
+	if len(raw) > maxIngestBody {
+		s.writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("payload exceeds %d bytes", maxIngestBody))
+		return
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
 	decoder.DisallowUnknownFields()
 	if err := decoder.Decode(&payload); err != nil {
 		s.writeError(w, http.StatusBadRequest, "invalid payload")
 		return
 	}
 
-	if payload.Headline == "" || payload.URL == "" {
-		s.writeError(w, http.StatusBadRequest, "headline and url are required")
-		return
+	key := ""
+	if s.idempotency != nil {
+		key = idempotencyKey(r)
+	}
+	var (
+		hash       string
+		slot       *idempotencySlot
+		unlockSlot func()
+	)
+	if key != "" {
+		// Serialise requests with the same key so concurrent retries cannot both
+		// store the item; other keys proceed in parallel.
+		hash = payloadHash(payload)
+		slot = s.acquireIdempotency(key)
+		defer s.releaseIdempotency(key, slot)
+		held := true
+		defer func() {
+			if held {
+				slot.mu.Unlock()
+			}
+		}()
+		unlockSlot = func() {
+			slot.mu.Unlock()
+			held = false
+		}
+
+		if previous, found := s.lookupIdempotency(key, slot); found {
+			if previous.PayloadHash != hash {
+				s.writeError(w, http.StatusConflict, "idempotency key reused with a different payload")
+				return
+			}
+			writeStoredResponse(w, previous)
+			return
+		}
 	}
 
 	published := time.Now().UTC()
+	var timestampFormat string
 	if payload.PublishedAt != "" {
-		ts, err := time.Parse(time.RFC3339, payload.PublishedAt)
+		ts, format, err := radar.ParseTimestampWith(payload.PublishedAt, s.timestamps)
 		if err != nil {
-			s.writeError(w, http.StatusBadRequest, "published_at must be RFC3339")
+			s.writeError(w, http.StatusBadRequest, "published_at: "+err.Error())
 			return
 		}
-		published = ts
+		published, timestampFormat = ts, format
 	}
 
 	news := radar.NewsItem{
@@ -132,12 +262,12 @@ func (s *Server) handleIngest(w http.ResponseWriter, r *http.Request) {
 		Headline:      payload.Headline,
 		Summary:       payload.Summary,
 		Body:          payload.Body,
-		Source:        defaultString(payload.Source, "ingest"),
+		Source:        payload.Source,
 		URL:           payload.URL,
-		Language:      defaultString(payload.Language, "en"),
+		Language:      payload.Language,
 		PublishedAt:   published,
-		Tickers:       dedupeStrings(payload.Tickers),
-		Entities:      dedupeStrings(payload.Entities),
+		Tickers:       payload.Tickers,
+		Entities:      payload.Entities,
 		Country:       payload.Country,
 		Category:      payload.Category,
 		ImportanceTag: payload.ImportanceTag,
@@ -146,6 +276,23 @@ func (s *Server) handleIngest(w http.ResponseWriter, r *http.Request) {
 		news.Sentiment = *payload.Sentiment
 	}
 
+	news, warnings, err := radar.Normalize(news, radar.NormalizeOptions{
+		DefaultSource:   "ingest",
+		DefaultLanguage: "en",
+		MaxAge:          s.maxAge,
+		RejectStale:     s.rejectStale,
+	})
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if timestampFormat != "" {
+		warnings = append(warnings, radar.Warning{
+			Field:   "published_at",
+			Message: fmt.Sprintf("parsed as %s; send RFC3339 with an offset", timestampFormat),
+		})
+	}
+
 	stored := s.ingest.Add(news)
 
 	response := map[string]any{
@@ -153,38 +300,32 @@ func (s *Server) handleIngest(w http.ResponseWriter, r *http.Request) {
 		"id":           stored.ID,
 		"published_at": stored.PublishedAt,
 	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusAccepted)
-	_ = json.NewEncoder(w).Encode(response)
-}
-
-func defaultString(value, fallback string) string {
-	if strings.TrimSpace(value) == "" {
-		return fallback
+	if stored.Stale {
+		response["stale"] = true
+	}
+	if len(warnings) > 0 {
+		response["warnings"] = warnings
 	}
-	return value
-}
 
-func dedupeStrings(values []string) []string {
-	if len(values) <= 1 {
-		return values
+	body, err := json.Marshal(response)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "encode response")
+		return
 	}
-	seen := make(map[string]struct{}, len(values))
-	var out []string
-	for _, v := range values {
-		v = strings.TrimSpace(v)
-		if v == "" {
-			continue
+	body = append(body, '\n')
+
+	if key != "" {
+		// Retries are answered from the slot while the store is written.
+		slot.done = &idempotentResponse{Status: http.StatusAccepted, Body: body, PayloadHash: hash}
+		unlockSlot()
+		if err := s.idempotency.Put(key, *slot.done, s.idempotencyTTL); err != nil {
+			log.Printf("idempotency store failed: %v", err)
 		}
-		key := strings.ToUpper(v)
-		if _, ok := seen[key]; ok {
-			continue
-		}
-		seen[key] = struct{}{}
-		out = append(out, v)
 	}
-	return out
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_, _ = w.Write(body)
 }
 
 func (s *Server) writeError(w http.ResponseWriter, status int, message string) {
@@ -193,11 +334,36 @@ func (s *Server) writeError(w http.ResponseWriter, status int, message string) {
 	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
 }
 
+// writePipelineError maps radar domain errors onto HTTP statuses and stable codes.
+func (s *Server) writePipelineError(w http.ResponseWriter, err error) {
+	status, code := http.StatusInternalServerError, "internal"
+
+	var sourceErr *radar.SourceError
+	var clusterErr *radar.ClusterError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		status, code = http.StatusGatewayTimeout, "timeout"
+	case errors.Is(err, radar.ErrCanceled):
+		status, code = http.StatusServiceUnavailable, "canceled"
+	case errors.Is(err, radar.ErrNoSources):
+		status, code = http.StatusServiceUnavailable, "no_sources"
+	case errors.As(err, &sourceErr):
+		status, code = http.StatusBadGateway, "source_failed"
+	case errors.As(err, &clusterErr):
+		status, code = http.StatusBadGateway, "cluster_failed"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error(), "code": code})
+}
+
 type timeframe struct {
 	from     time.Time
 	to       time.Time
 	limit    int
 	language string
+	asOf     time.Time
 }
 
 func (s *Server) parseParams(r *http.Request) timeframe {
@@ -210,13 +376,24 @@ func (s *Server) parseParams(r *http.Request) timeframe {
 		}
 	}
 
-	now := time.Now().UTC()
+	now := s.now()
+	var asOf time.Time
+	if v := values.Get("as_of"); v != "" {
+		if parsed, err := time.Parse(time.RFC3339, v); err == nil && !parsed.After(now) {
+			asOf = parsed.UTC()
+			now = asOf
+		}
+	}
+
 	to := now
 	if v := values.Get("to"); v != "" {
 		if parsed, err := time.Parse(time.RFC3339, v); err == nil {
 			to = parsed
 		}
 	}
+	if !asOf.IsZero() && to.After(asOf) {
+		to = asOf
+	}
 
 	from := to.Add(-s.defaultWindow)
 
@@ -238,5 +415,5 @@ func (s *Server) parseParams(r *http.Request) timeframe {
 
 	language := values.Get("lang")
 
-	return timeframe{from: from, to: to, limit: limit, language: language}
+	return timeframe{from: from, to: to, limit: limit, language: language, asOf: asOf}
 }
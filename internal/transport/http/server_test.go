@@ -3,9 +3,11 @@ package transporthttp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -53,3 +55,288 @@ func TestRadarEndpoint(t *testing.T) {
 		t.Fatalf("expected at least one event")
 	}
 }
+
+type failingSource struct{}
+
+func (failingSource) Name() string { return "wire" }
+
+func (failingSource) Fetch(ctx context.Context, from, to time.Time) ([]radar.NewsItem, error) {
+	return nil, errors.New("upstream down")
+}
+
+func TestRadarEndpointMapsSourceError(t *testing.T) {
+	sources, err := radar.NewSourceRegistry(failingSource{})
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+
+	pipeline, err := radar.NewPipeline(sources, radar.DefaultClusterer(), radar.DefaultScorer())
+	if err != nil {
+		t.Fatalf("pipeline: %v", err)
+	}
+
+	srv := NewServer(pipeline, config.Config{DefaultWindow: 24 * time.Hour, TopK: 2}, nil)
+
+	rec := httptest.NewRecorder()
+	srv.handleRadar(rec, httptest.NewRequest(http.MethodGet, "/radar", nil))
+
+	if rec.Code != http.StatusBadGateway {
+		t.Fatalf("expected status 502, got %d", rec.Code)
+	}
+
+	var payload struct {
+		Code string `json:"code"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if payload.Code != "source_failed" {
+		t.Fatalf("unexpected error code: %q", payload.Code)
+	}
+}
+
+func TestRadarEndpointServesEmptyWindow(t *testing.T) {
+	ingest := radar.NewIngestSource("test-ingest")
+	sources, err := radar.NewSourceRegistry(ingest)
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+
+	pipeline, err := radar.NewPipeline(sources, radar.DefaultClusterer(), radar.DefaultScorer())
+	if err != nil {
+		t.Fatalf("pipeline: %v", err)
+	}
+
+	srv := NewServer(pipeline, config.Config{DefaultWindow: 24 * time.Hour, TopK: 2}, ingest)
+
+	rec := httptest.NewRecorder()
+	srv.handleRadar(rec, httptest.NewRequest(http.MethodGet, "/radar", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+}
+
+func TestIngestEndpointNormalizesItem(t *testing.T) {
+	ingest := radar.NewIngestSource("test-ingest")
+	sources, err := radar.NewSourceRegistry(ingest)
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+	pipeline, err := radar.NewPipeline(sources, radar.DefaultClusterer(), radar.DefaultScorer())
+	if err != nil {
+		t.Fatalf("pipeline: %v", err)
+	}
+	srv := NewServer(pipeline, config.Config{DefaultWindow: 24 * time.Hour, TopK: 2}, ingest)
+
+	body := `{"id":"n1","headline":" Сбербанк повысил прогноз ","url":"https://Example.com/a?utm_source=tg","published_at":"Fri, 03 Oct 2025 08:00:00 GMT","tickers":["sber"," SBER"]}`
+	rec := httptest.NewRecorder()
+	srv.handleIngest(rec, httptest.NewRequest(http.MethodPost, "/news", strings.NewReader(body)))
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var payload struct {
+		Warnings []radar.Warning `json:"warnings"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(payload.Warnings) != 2 || payload.Warnings[0].Field != "language" || payload.Warnings[1].Field != "published_at" {
+		t.Fatalf("expected language and published_at warnings, got %+v", payload.Warnings)
+	}
+
+	items, err := ingest.Fetch(context.Background(), time.Date(2025, 10, 3, 0, 0, 0, 0, time.UTC), time.Date(2025, 10, 4, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expected 1 stored item, got %d", len(items))
+	}
+	got := items[0]
+	if got.Headline != "Сбербанк повысил прогноз" || got.URL != "https://example.com/a" || got.Language != "ru" || got.Source != "ingest" {
+		t.Errorf("item not normalized: %+v", got)
+	}
+	if len(got.Tickers) != 1 || got.Tickers[0] != "SBER" {
+		t.Errorf("tickers not normalized: %v", got.Tickers)
+	}
+}
+
+func TestHeatmapEndpoint(t *testing.T) {
+	ingest := radar.NewIngestSource("test-ingest")
+	base := time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)
+	ingest.Add(radar.NewsItem{ID: "n1", Headline: "NordTech cuts guidance", URL: "https://example.com/1", Source: "Reuters", PublishedAt: base.Add(10 * time.Minute), Tickers: []string{"NTCH"}})
+	ingest.Add(radar.NewsItem{ID: "n2", Headline: "NordTech supplier fire", URL: "https://example.com/2", Source: "Bloomberg", PublishedAt: base.Add(70 * time.Minute), Tickers: []string{"NTCH"}})
+
+	sources, err := radar.NewSourceRegistry(ingest)
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+	pipeline, err := radar.NewPipeline(sources, radar.DefaultClusterer(), radar.DefaultScorer())
+	if err != nil {
+		t.Fatalf("pipeline: %v", err)
+	}
+	srv := NewServer(pipeline, config.Config{DefaultWindow: 24 * time.Hour, TopK: 2}, ingest)
+
+	rec := httptest.NewRecorder()
+	srv.Routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/radar/heatmap?window_hours=3&bucket=1h&to=2025-10-03T09:30:00Z", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body)
+	}
+
+	var payload struct {
+		Heatmap radar.Heatmap `json:"heatmap"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if len(payload.Heatmap.Buckets) != 4 || !payload.Heatmap.Buckets[0].Equal(time.Date(2025, 10, 3, 7, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected buckets: %v", payload.Heatmap.Buckets)
+	}
+	if len(payload.Heatmap.Tickers) != 1 || payload.Heatmap.Tickers[0] != "NTCH" {
+		t.Fatalf("unexpected tickers: %v", payload.Heatmap.Tickers)
+	}
+	row := payload.Heatmap.Values[0]
+	if len(row) != 3 || row[0] != 0 || row[1] == 0 || row[1] != row[2] {
+		t.Fatalf("unexpected row: %v", row)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.Routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/radar/heatmap?bucket=30m", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("sub-hour bucket should be rejected, got %d", rec.Code)
+	}
+}
+
+func TestDebugClustersEndpointExposesConfidence(t *testing.T) {
+	ingest := radar.NewIngestSource("test-ingest")
+	base := time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)
+	ingest.Add(radar.NewsItem{ID: "n1", Headline: "NordTech cuts guidance", URL: "https://example.com/1", PublishedAt: base, Tickers: []string{"NTCH"}})
+
+	sources, err := radar.NewSourceRegistry(ingest)
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+	pipeline, err := radar.NewPipeline(sources, radar.DefaultClusterer(), radar.DefaultScorer())
+	if err != nil {
+		t.Fatalf("pipeline: %v", err)
+	}
+	srv := NewServer(pipeline, config.Config{DefaultWindow: 24 * time.Hour, TopK: 2}, ingest)
+
+	rec := httptest.NewRecorder()
+	srv.Routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/clusters?from=2025-10-03T00:00:00Z&to=2025-10-04T00:00:00Z", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	var payload struct {
+		Clusters []debugCluster `json:"clusters"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(payload.Clusters) != 1 || payload.Clusters[0].Confidence != 0.5 || payload.Clusters[0].NewsIDs[0] != "n1" {
+		t.Fatalf("unexpected clusters: %+v", payload.Clusters)
+	}
+}
+
+func TestRadarEndpointAsOfStampsMeta(t *testing.T) {
+	ingest := radar.NewIngestSource("test-ingest")
+	base := time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)
+	ingest.Add(radar.NewsItem{ID: "n1", Headline: "Early", Source: "reuters", PublishedAt: base, IngestedAt: base, Tickers: []string{"SBER"}})
+	ingest.Add(radar.NewsItem{ID: "n2", Headline: "Late", Source: "reuters", PublishedAt: base, IngestedAt: base.Add(3 * time.Hour), Tickers: []string{"GAZP"}})
+	sources, err := radar.NewSourceRegistry(ingest)
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+	pipeline, err := radar.NewPipeline(sources, radar.DefaultClusterer(), radar.DefaultScorer())
+	if err != nil {
+		t.Fatalf("pipeline: %v", err)
+	}
+	srv := NewServer(pipeline, config.Config{DefaultWindow: 24 * time.Hour, TopK: 5}, ingest)
+
+	rec := httptest.NewRecorder()
+	srv.handleRadar(rec, httptest.NewRequest(http.MethodGet, "/radar?as_of=2025-10-03T09:00:00Z", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var payload struct {
+		To     time.Time     `json:"to"`
+		Events []radar.Event `json:"events"`
+		Meta   struct {
+			Historical   bool      `json:"historical"`
+			AsOf         time.Time `json:"as_of"`
+			Degradations []string  `json:"degradations"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if !payload.Meta.Historical || !payload.Meta.AsOf.Equal(base.Add(time.Hour)) || len(payload.Meta.Degradations) == 0 {
+		t.Fatalf("unexpected meta: %+v", payload.Meta)
+	}
+	if !payload.To.Equal(base.Add(time.Hour)) {
+		t.Errorf("expected window to end at as_of, got %s", payload.To)
+	}
+	if len(payload.Events) != 1 || payload.Events[0].Tickers[0] != "SBER" {
+		t.Fatalf("expected only the early event, got %+v", payload.Events)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.handleRadar(rec, httptest.NewRequest(http.MethodGet, "/radar?as_of=yesterday", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for malformed as_of, got %d", rec.Code)
+	}
+}
+
+func TestIngestEndpointEnforcesMaxAge(t *testing.T) {
+	newServer := func(mode string) (*Server, *radar.IngestSource) {
+		ingest := radar.NewIngestSource("test-ingest")
+		sources, err := radar.NewSourceRegistry(ingest)
+		if err != nil {
+			t.Fatalf("registry: %v", err)
+		}
+		pipeline, err := radar.NewPipeline(sources, radar.DefaultClusterer(), radar.DefaultScorer())
+		if err != nil {
+			t.Fatalf("pipeline: %v", err)
+		}
+		cfg := config.Config{DefaultWindow: 24 * time.Hour, TopK: 2, IngestMaxAge: 7 * 24 * time.Hour, IngestStaleMode: mode}
+		return NewServer(pipeline, cfg, ingest), ingest
+	}
+	old := time.Now().UTC().Add(-60 * 24 * time.Hour).Format(time.RFC3339)
+	body := `{"id":"old","headline":"Backfilled guidance cut","url":"https://example.com/old","language":"en","published_at":"` + old + `"}`
+
+	srv, ingest := newServer("reject")
+	rec := httptest.NewRecorder()
+	srv.handleIngest(rec, httptest.NewRequest(http.MethodPost, "/news", strings.NewReader(body)))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for a stale item, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if items, _ := ingest.Fetch(context.Background(), time.Time{}, time.Now()); len(items) != 0 {
+		t.Fatalf("rejected item must not be stored, got %d", len(items))
+	}
+
+	srv, ingest = newServer("flag")
+	rec = httptest.NewRecorder()
+	srv.handleIngest(rec, httptest.NewRequest(http.MethodPost, "/news", strings.NewReader(body)))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202 in flag mode, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var payload struct {
+		Stale    bool            `json:"stale"`
+		Warnings []radar.Warning `json:"warnings"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if !payload.Stale || len(payload.Warnings) != 1 || payload.Warnings[0].Field != "published_at" {
+		t.Fatalf("expected a stale flag and warning, got %+v", payload)
+	}
+	items, _ := ingest.Fetch(context.Background(), time.Time{}, time.Now())
+	if len(items) != 1 || !items[0].Stale {
+		t.Fatalf("expected the stored item to be flagged stale, got %+v", items)
+	}
+}